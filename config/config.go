@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/caarlos0/env"
 	"github.com/joho/godotenv"
@@ -22,13 +23,36 @@ type Config struct {
 
 	FEURL string `env:"FEURL"`
 
+	// ProxyHeader names the header carrying the client IP, e.g. X-Real-IP. It
+	// is only honoured for requests coming from TrustedProxies.
+	ProxyHeader    string   `env:"PROXY_HEADER"`
+	TrustedProxies []string `env:"TRUSTED_PROXIES" envSeparator:","`
+
 	AccessSecret  string `env:"ACCESS_SECRET"`
 	RefreshSecret string `env:"REFRESH_SECRET"`
 
+	JWTPrivateKeyFile string   `env:"JWT_PRIVATE_KEY_FILE"`
+	JWTPublicKeyFiles []string `env:"JWT_PUBLIC_KEY_FILES" envSeparator:","`
+
+	AccessTokenExpiry            time.Duration `env:"ACCESS_TOKEN_EXPIRY" envDefault:"15m"`
+	RefreshTokenExpiry           time.Duration `env:"REFRESH_TOKEN_EXPIRY" envDefault:"168h"`
+	RememberMeRefreshTokenExpiry time.Duration `env:"REMEMBER_ME_REFRESH_TOKEN_EXPIRY" envDefault:"720h"`
+	TokenRevocationCacheTTL      time.Duration `env:"TOKEN_REVOCATION_CACHE_TTL" envDefault:"5s"`
+
 	EmailUser     string `env:"EMAIL_USER"`
 	EmailPassword string `env:"EMAIL_PASSWORD"`
 
-	OTPExpiry time.Duration `env:"OTP_EXPIRY"`
+	OTPExpiry         time.Duration `env:"OTP_EXPIRY"`
+	OTPResendCooldown time.Duration `env:"OTP_RESEND_COOLDOWN" envDefault:"60s"`
+	OTPDailyLimit     int           `env:"OTP_DAILY_LIMIT" envDefault:"10"`
+
+	MaxLoginAttempts      int           `env:"MAX_LOGIN_ATTEMPTS" envDefault:"5"`
+	MaxLoginAttemptsPerIP int           `env:"MAX_LOGIN_ATTEMPTS_PER_IP" envDefault:"20"`
+	MaxOTPAttempts        int           `env:"MAX_OTP_ATTEMPTS" envDefault:"5"`
+	AttemptWindow         time.Duration `env:"ATTEMPT_WINDOW" envDefault:"15m"`
+	LockoutBaseDuration   time.Duration `env:"LOCKOUT_BASE_DURATION" envDefault:"1m"`
+	LockoutMaxDuration    time.Duration `env:"LOCKOUT_MAX_DURATION" envDefault:"1h"`
+	LockoutResetWindow    time.Duration `env:"LOCKOUT_RESET_WINDOW" envDefault:"24h"`
 
 	RedisHost     string `env:"REDIS_HOST"`
 	RedisPort     int    `env:"REDIS_PORT"`
@@ -41,8 +65,47 @@ type Config struct {
 
 	StateLength int           `env:"STATE_LENGTH"`
 	StateExpiry time.Duration `env:"STATE_EXPIRY"`
+
+	MFAIssuer          string        `env:"MFA_ISSUER" envDefault:"Eco Trace"`
+	MFAChallengeExpiry time.Duration `env:"MFA_CHALLENGE_EXPIRY" envDefault:"5m"`
+	MFAEncryptionKey   string        `env:"MFA_ENCRYPTION_KEY"`
+
+	MaxSessions        int    `env:"MAX_SESSIONS" envDefault:"2"`
+	SessionLimitPolicy string `env:"SESSION_LIMIT_POLICY" envDefault:"evict_oldest"`
+
+	AdminEmails []string `env:"ADMIN_EMAILS" envSeparator:","`
+
+	StorageDir        string `env:"STORAGE_DIR" envDefault:"uploads"`
+	StoragePublicPath string `env:"STORAGE_PUBLIC_PATH" envDefault:"/uploads"`
+	PrivateStorageDir string `env:"PRIVATE_STORAGE_DIR" envDefault:"private"`
+	MaxImageSize      int64  `env:"MAX_IMAGE_SIZE" envDefault:"2097152"`
+	MaxTrackSize      int64  `env:"MAX_TRACK_SIZE" envDefault:"10485760"`
+
+	ChallengeRetakeCooldown time.Duration `env:"CHALLENGE_RETAKE_COOLDOWN" envDefault:"24h"`
+	ChallengeExpiryInterval time.Duration `env:"CHALLENGE_EXPIRY_INTERVAL" envDefault:"1m"`
+	BadgeBackfillInterval   time.Duration `env:"BADGE_BACKFILL_INTERVAL" envDefault:"1m"`
+	MaxOngoingChallenges    int           `env:"MAX_ONGOING_CHALLENGES" envDefault:"5"`
+	ChallengeTimezone       string        `env:"CHALLENGE_TIMEZONE" envDefault:"Asia/Jakarta"`
+	TrackMinDistanceMeters  float64       `env:"TRACK_MIN_DISTANCE_METERS" envDefault:"500"`
+
+	LevelCurve      string  `env:"LEVEL_CURVE" envDefault:"formula"`
+	LevelBaseExp    int     `env:"LEVEL_BASE_EXP" envDefault:"100"`
+	LevelGrowth     float64 `env:"LEVEL_GROWTH" envDefault:"1.2"`
+	LevelMax        int     `env:"LEVEL_MAX" envDefault:"50"`
+	LevelThresholds []int   `env:"LEVEL_THRESHOLDS" envSeparator:","`
+
+	FootprintDefaultRegion string `env:"FOOTPRINT_DEFAULT_REGION" envDefault:"ID"`
+
+	// Location is ChallengeTimezone loaded once at startup. Challenge periods,
+	// activity days and impact buckets are all computed in it.
+	Location *time.Location
 }
 
+const (
+	SessionPolicyEvictOldest = "evict_oldest"
+	SessionPolicyReject      = "reject"
+)
+
 func New() (*Config, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Printf("Warning: .env file not found: %v", err)
@@ -53,5 +116,11 @@ func New() (*Config, error) {
 		return nil, err
 	}
 
+	location, err := time.LoadLocation(cfg.ChallengeTimezone)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Location = location
+
 	return cfg, nil
 }
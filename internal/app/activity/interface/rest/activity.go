@@ -0,0 +1,170 @@
+package rest
+
+import (
+	"github.com/Ablebil/eco-sample/internal/app/activity/usecase"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ActivityHandler struct {
+	validator       *validator.Validate
+	activityUsecase usecase.ActivityUsecaseItf
+}
+
+func NewActivityHandler(activityGroup fiber.Router, validator *validator.Validate, activityUsecase usecase.ActivityUsecaseItf, middleware middleware.MiddlewareItf) {
+	activityHandler := ActivityHandler{
+		validator:       validator,
+		activityUsecase: activityUsecase,
+	}
+
+	activityGroup = activityGroup.Group("/activities")
+	activityGroup.Get("/", middleware.Authentication, activityHandler.GetActivities)
+	activityGroup.Post("/", middleware.Authentication, activityHandler.CreateActivity)
+	activityGroup.Get("/:id", middleware.Authentication, activityHandler.GetActivity)
+	activityGroup.Patch("/:id", middleware.Authentication, activityHandler.UpdateActivity)
+	activityGroup.Delete("/:id", middleware.Authentication, activityHandler.DeleteActivity)
+}
+
+func (h *ActivityHandler) GetActivities(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := new(dto.GetActivitiesQuery)
+	if err := ctx.QueryParser(query); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	activities, errRes := h.activityUsecase.GetActivities(userID, *query)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, activities)
+}
+
+func (h *ActivityHandler) CreateActivity(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.CreateActivityRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	activity, errRes := h.activityUsecase.CreateActivity(userID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.Created(ctx, activity, res.CreateActivitySuccess)
+}
+
+func (h *ActivityHandler) GetActivity(ctx *fiber.Ctx) error {
+	userID, errRes := getUserIDFromContext(ctx)
+	if errRes != nil {
+		return errRes
+	}
+
+	activityID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	activity, errRes := h.activityUsecase.GetActivity(userID, activityID)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, activity)
+}
+
+func (h *ActivityHandler) UpdateActivity(ctx *fiber.Ctx) error {
+	userID, errRes := getUserIDFromContext(ctx)
+	if errRes != nil {
+		return errRes
+	}
+
+	activityID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	req := new(dto.UpdateActivityRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	activity, errRes := h.activityUsecase.UpdateActivity(userID, activityID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, activity, res.UpdateActivitySuccess)
+}
+
+func (h *ActivityHandler) DeleteActivity(ctx *fiber.Ctx) error {
+	userID, errRes := getUserIDFromContext(ctx)
+	if errRes != nil {
+		return errRes
+	}
+
+	activityID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if errRes := h.activityUsecase.DeleteActivity(userID, activityID); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.DeleteActivitySuccess)
+}
+
+func getUserIDFromContext(ctx *fiber.Ctx) (uuid.UUID, *res.Err) {
+	userIDStr := ctx.Locals("user_id")
+	if userIDStr == nil {
+		return uuid.Nil, res.ErrUnauthorized("User not authenticated")
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		return uuid.Nil, res.ErrUnauthorized("Invalid user ID")
+	}
+
+	return userID, nil
+}
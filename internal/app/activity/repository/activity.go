@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ActivityRepositoryItf interface {
+	CreateActivity(activity *entity.ActivityLog) error
+	GetActivityByID(userID, id uuid.UUID) (*entity.ActivityLog, error)
+	GetActivities(userID uuid.UUID, filter ActivityFilter, limit, offset int) ([]entity.ActivityLog, int64, error)
+	UpdateActivity(activity *entity.ActivityLog) error
+	DeleteActivity(userID, id uuid.UUID) (bool, error)
+	GetDailyTotals(userID uuid.UUID, from time.Time) ([]ActivityDailyTotal, error)
+}
+
+type ActivityFilter struct {
+	Type entity.ActivityType
+	From *time.Time
+	To   *time.Time
+}
+
+// ActivityDailyTotal is the sum of one user's activities of one type on one
+// day.
+type ActivityDailyTotal struct {
+	Day      time.Time
+	Type     entity.ActivityType
+	Entries  int
+	Quantity float64
+	Co2eKg   float64
+}
+
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) ActivityRepositoryItf {
+	return &ActivityRepository{db}
+}
+
+func (r *ActivityRepository) CreateActivity(activity *entity.ActivityLog) error {
+	return r.db.Create(activity).Error
+}
+
+func (r *ActivityRepository) GetActivityByID(userID, id uuid.UUID) (*entity.ActivityLog, error) {
+	var activity entity.ActivityLog
+	err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&activity).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &activity, nil
+}
+
+func (r *ActivityRepository) GetActivities(userID uuid.UUID, filter ActivityFilter, limit, offset int) ([]entity.ActivityLog, int64, error) {
+	query := r.db.Model(&entity.ActivityLog{}).Where("user_id = ?", userID)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	if filter.From != nil {
+		query = query.Where("occurred_on >= ?", filter.From.Format(time.DateOnly))
+	}
+
+	if filter.To != nil {
+		query = query.Where("occurred_on <= ?", filter.To.Format(time.DateOnly))
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var activities []entity.ActivityLog
+	err := query.Order("occurred_on DESC, created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&activities).Error
+
+	return activities, total, err
+}
+
+func (r *ActivityRepository) UpdateActivity(activity *entity.ActivityLog) error {
+	return r.db.Model(activity).
+		Select("mode", "quantity", "co2e_kg", "region", "factor_version", "occurred_on", "note").
+		Updates(activity).Error
+}
+
+func (r *ActivityRepository) DeleteActivity(userID, id uuid.UUID) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entity.ActivityLog{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ActivityRepository) GetDailyTotals(userID uuid.UUID, from time.Time) ([]ActivityDailyTotal, error) {
+	var totals []ActivityDailyTotal
+	err := r.db.Model(&entity.ActivityLog{}).
+		Select("occurred_on AS day, type, COUNT(*) AS entries, SUM(quantity) AS quantity, SUM(co2e_kg) AS co2e_kg").
+		Where("user_id = ? AND occurred_on >= ?", userID, from.Format(time.DateOnly)).
+		Group("occurred_on, type").
+		Order("occurred_on ASC").
+		Scan(&totals).Error
+
+	return totals, err
+}
@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"math"
+	"time"
+
+	"github.com/Ablebil/eco-sample/config"
+	activityRepository "github.com/Ablebil/eco-sample/internal/app/activity/repository"
+	footprintRepository "github.com/Ablebil/eco-sample/internal/app/footprint/repository"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/google/uuid"
+)
+
+type ActivityUsecaseItf interface {
+	CreateActivity(userID uuid.UUID, req dto.CreateActivityRequest) (*dto.ActivityResponse, *res.Err)
+	GetActivities(userID uuid.UUID, query dto.GetActivitiesQuery) (*dto.GetActivitiesResponse, *res.Err)
+	GetActivity(userID, id uuid.UUID) (*dto.ActivityResponse, *res.Err)
+	UpdateActivity(userID, id uuid.UUID, req dto.UpdateActivityRequest) (*dto.ActivityResponse, *res.Err)
+	DeleteActivity(userID, id uuid.UUID) *res.Err
+	GetActivityTrend(userID uuid.UUID) (*dto.ActivityTrendResponse, *res.Err)
+}
+
+type ActivityUsecase struct {
+	activityRepository  activityRepository.ActivityRepositoryItf
+	footprintRepository footprintRepository.FootprintRepositoryItf
+	cfg                 *config.Config
+	location            *time.Location
+}
+
+func NewActivityUsecase(activityRepository activityRepository.ActivityRepositoryItf, footprintRepository footprintRepository.FootprintRepositoryItf, cfg *config.Config) ActivityUsecaseItf {
+	return &ActivityUsecase{
+		activityRepository:  activityRepository,
+		footprintRepository: footprintRepository,
+		cfg:                 cfg,
+		location:            cfg.Location,
+	}
+}
+
+type activitySpec struct {
+	category    entity.EmissionCategory
+	unit        string
+	maxQuantity float64
+}
+
+// activitySpecs describes how each activity type is measured and converted.
+// Transport entries are looked up by their mode, electricity by the grid of
+// the user's region.
+var activitySpecs = map[entity.ActivityType]activitySpec{
+	entity.ActivityTransport:   {category: entity.EmissionTransport, unit: "km", maxQuantity: 2000},
+	entity.ActivityElectricity: {category: entity.EmissionElectricity, unit: "kWh", maxQuantity: 1000},
+}
+
+const (
+	gridFactorKey = "grid"
+
+	defaultActivitiesLimit = 20
+	activityTrendDays      = 7
+)
+
+func (uc *ActivityUsecase) CreateActivity(userID uuid.UUID, req dto.CreateActivityRequest) (*dto.ActivityResponse, *res.Err) {
+	activity := &entity.ActivityLog{
+		UserID:   userID,
+		Type:     entity.ActivityType(req.Type),
+		Quantity: req.Quantity,
+		Note:     req.Note,
+	}
+
+	if req.Mode != "" {
+		activity.Mode = &req.Mode
+	}
+
+	occurredOn, errRes := uc.parseOccurredOn(req.OccurredOn)
+	if errRes != nil {
+		return nil, errRes
+	}
+	activity.OccurredOn = occurredOn
+
+	if errRes := uc.convert(activity); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.activityRepository.CreateActivity(activity); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedCreateActivity)
+	}
+
+	response := toActivityResponse(activity)
+	return &response, nil
+}
+
+func (uc *ActivityUsecase) GetActivities(userID uuid.UUID, query dto.GetActivitiesQuery) (*dto.GetActivitiesResponse, *res.Err) {
+	page := max(query.Page, 1)
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultActivitiesLimit
+	}
+
+	filter := activityRepository.ActivityFilter{Type: entity.ActivityType(query.Type)}
+	if query.From != "" {
+		from, err := time.Parse(time.DateOnly, query.From)
+		if err != nil {
+			return nil, res.ErrBadRequest(res.FailedParsingRequestParams)
+		}
+		filter.From = &from
+	}
+
+	if query.To != "" {
+		to, err := time.Parse(time.DateOnly, query.To)
+		if err != nil {
+			return nil, res.ErrBadRequest(res.FailedParsingRequestParams)
+		}
+		filter.To = &to
+	}
+
+	activities, total, err := uc.activityRepository.GetActivities(userID, filter, limit, (page-1)*limit)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetActivities)
+	}
+
+	response := &dto.GetActivitiesResponse{
+		Activities: make([]dto.ActivityResponse, 0, len(activities)),
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+	}
+
+	for _, activity := range activities {
+		response.Activities = append(response.Activities, toActivityResponse(&activity))
+	}
+
+	return response, nil
+}
+
+func (uc *ActivityUsecase) GetActivity(userID, id uuid.UUID) (*dto.ActivityResponse, *res.Err) {
+	activity, err := uc.activityRepository.GetActivityByID(userID, id)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetActivities)
+	}
+
+	if activity == nil {
+		return nil, res.ErrNotFound(res.ActivityNotFound)
+	}
+
+	response := toActivityResponse(activity)
+	return &response, nil
+}
+
+func (uc *ActivityUsecase) UpdateActivity(userID, id uuid.UUID, req dto.UpdateActivityRequest) (*dto.ActivityResponse, *res.Err) {
+	activity, err := uc.activityRepository.GetActivityByID(userID, id)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetActivities)
+	}
+
+	if activity == nil {
+		return nil, res.ErrNotFound(res.ActivityNotFound)
+	}
+
+	if req.Mode != nil {
+		if activity.Type != entity.ActivityTransport {
+			return nil, res.ErrBadRequest(res.ActivityModeNotAllowed)
+		}
+		activity.Mode = req.Mode
+	}
+
+	if req.Quantity != nil {
+		activity.Quantity = *req.Quantity
+	}
+
+	if req.OccurredOn != nil {
+		occurredOn, errRes := uc.parseOccurredOn(*req.OccurredOn)
+		if errRes != nil {
+			return nil, errRes
+		}
+		activity.OccurredOn = occurredOn
+	}
+
+	if req.Note != nil {
+		activity.Note = req.Note
+	}
+
+	if errRes := uc.convert(activity); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.activityRepository.UpdateActivity(activity); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUpdateActivity)
+	}
+
+	response := toActivityResponse(activity)
+	return &response, nil
+}
+
+func (uc *ActivityUsecase) DeleteActivity(userID, id uuid.UUID) *res.Err {
+	deleted, err := uc.activityRepository.DeleteActivity(userID, id)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedDeleteActivity)
+	}
+
+	if !deleted {
+		return res.ErrNotFound(res.ActivityNotFound)
+	}
+
+	return nil
+}
+
+// GetActivityTrend summarises the user's logged activity emissions for the
+// last week, day by day, and compares them with the week before. Days are
+// calendar days in the configured location.
+func (uc *ActivityUsecase) GetActivityTrend(userID uuid.UUID) (*dto.ActivityTrendResponse, *res.Err) {
+	weekStart := uc.today().AddDate(0, 0, -(activityTrendDays - 1))
+	previousWeekStart := weekStart.AddDate(0, 0, -activityTrendDays)
+
+	totals, err := uc.activityRepository.GetDailyTotals(userID, previousWeekStart)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetActivityTrend)
+	}
+
+	trend := &dto.ActivityTrendResponse{
+		Daily: make([]dto.ActivityDayResponse, activityTrendDays),
+	}
+
+	index := make(map[string]int, activityTrendDays)
+	for i := range trend.Daily {
+		trend.Daily[i].Date = weekStart.AddDate(0, 0, i).Format(time.DateOnly)
+		index[trend.Daily[i].Date] = i
+	}
+
+	weekStartKey := weekStart.Format(time.DateOnly)
+	for _, total := range totals {
+		day := total.Day.Format(time.DateOnly)
+		if day < weekStartKey {
+			trend.Previous7DaysKg += total.Co2eKg
+			continue
+		}
+
+		i, ok := index[day]
+		if !ok {
+			continue
+		}
+
+		trend.Last7DaysKg += total.Co2eKg
+		trend.Daily[i].Entries += total.Entries
+		trend.Daily[i].Co2eKg += total.Co2eKg
+
+		switch total.Type {
+		case entity.ActivityTransport:
+			trend.Daily[i].TransportKm += total.Quantity
+		case entity.ActivityElectricity:
+			trend.Daily[i].ElectricityKwh += total.Quantity
+		}
+	}
+
+	for i := range trend.Daily {
+		trend.Daily[i].Co2eKg = roundKg(trend.Daily[i].Co2eKg)
+	}
+	trend.Last7DaysKg = roundKg(trend.Last7DaysKg)
+	trend.Previous7DaysKg = roundKg(trend.Previous7DaysKg)
+
+	if trend.Previous7DaysKg > 0 {
+		change := math.Round((trend.Last7DaysKg-trend.Previous7DaysKg)/trend.Previous7DaysKg*1000) / 10
+		trend.ChangePercent = &change
+	}
+
+	return trend, nil
+}
+
+// convert validates the entry against its type and recalculates its CO2e with
+// the latest emission factors.
+func (uc *ActivityUsecase) convert(activity *entity.ActivityLog) *res.Err {
+	spec, ok := activitySpecs[activity.Type]
+	if !ok {
+		return res.ErrBadRequest(res.UnsupportedActivityType)
+	}
+
+	if activity.Quantity > spec.maxQuantity {
+		return res.ErrBadRequest(res.ActivityQuantityTooLarge)
+	}
+
+	factorKey := gridFactorKey
+	if activity.Type == entity.ActivityTransport {
+		factorKey = *activity.Mode
+	}
+
+	region := uc.cfg.FootprintDefaultRegion
+	factor, err := uc.footprintRepository.GetLatestEmissionFactor(spec.category, factorKey, region)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGetEmissionFactors)
+	}
+
+	if factor == nil {
+		if activity.Type == entity.ActivityTransport {
+			return res.ErrBadRequest(res.UnsupportedTransportMode)
+		}
+		return res.ErrBadRequest(res.UnsupportedRegion)
+	}
+
+	activity.Unit = spec.unit
+	activity.Region = region
+	activity.FactorVersion = factor.Version
+	activity.Co2eKg = roundKg(activity.Quantity * factor.Factor)
+
+	return nil
+}
+
+func (uc *ActivityUsecase) parseOccurredOn(value string) (time.Time, *res.Err) {
+	today := uc.today()
+	if value == "" {
+		return today, nil
+	}
+
+	occurredOn, err := time.ParseInLocation(time.DateOnly, value, uc.location)
+	if err != nil {
+		return time.Time{}, res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if occurredOn.After(today) {
+		return time.Time{}, res.ErrBadRequest(res.ActivityInFuture)
+	}
+
+	return occurredOn, nil
+}
+
+// today returns the start of the current calendar day in the configured
+// location.
+func (uc *ActivityUsecase) today() time.Time {
+	now := time.Now().In(uc.location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, uc.location)
+}
+
+func roundKg(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
+
+func toActivityResponse(activity *entity.ActivityLog) dto.ActivityResponse {
+	response := dto.ActivityResponse{
+		ID:            activity.ID,
+		Type:          string(activity.Type),
+		Mode:          activity.Mode,
+		Quantity:      activity.Quantity,
+		Unit:          activity.Unit,
+		Co2eKg:        activity.Co2eKg,
+		Region:        activity.Region,
+		FactorVersion: activity.FactorVersion,
+		OccurredOn:    activity.OccurredOn.Format(time.DateOnly),
+		Note:          activity.Note,
+	}
+
+	if activity.CreatedAt != nil {
+		response.CreatedAt = *activity.CreatedAt
+	}
+
+	return response
+}
@@ -3,13 +3,16 @@ package rest
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/Ablebil/eco-sample/config"
 	"github.com/Ablebil/eco-sample/internal/app/auth/usecase"
 	"github.com/Ablebil/eco-sample/internal/domain/dto"
 	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/middleware"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
@@ -18,7 +21,7 @@ type AuthHandler struct {
 	cfg         *config.Config
 }
 
-func NewAuthHandler(authGroup fiber.Router, validator *validator.Validate, authUsecase usecase.AuthUsecaseItf, cfg *config.Config) {
+func NewAuthHandler(authGroup fiber.Router, validator *validator.Validate, authUsecase usecase.AuthUsecaseItf, cfg *config.Config, middleware middleware.MiddlewareItf) {
 	authHandler := AuthHandler{
 		validator:   validator,
 		authUsecase: authUsecase,
@@ -28,11 +31,31 @@ func NewAuthHandler(authGroup fiber.Router, validator *validator.Validate, authU
 	authGroup = authGroup.Group("/auth")
 	authGroup.Post("/register", authHandler.Register)
 	authGroup.Post("/verify-otp", authHandler.VerifyOTP)
+	authGroup.Post("/resend-otp", authHandler.ResendOTP)
 	authGroup.Post("/login", authHandler.Login)
 	authGroup.Get("/google", authHandler.GoogleLogin)
 	authGroup.Get("/google/callback", authHandler.GoogleCallback)
 	authGroup.Post("/refresh-token", authHandler.RefreshToken)
 	authGroup.Post("/logout", authHandler.Logout)
+	authGroup.Post("/forgot-password", authHandler.ForgotPassword)
+	authGroup.Post("/reset-password", authHandler.ResetPassword)
+	authGroup.Get("/sessions", middleware.Authentication, authHandler.GetSessions)
+	authGroup.Delete("/sessions/:id", middleware.Authentication, authHandler.RevokeSession)
+	authGroup.Post("/logout-all", middleware.Authentication, authHandler.LogoutAll)
+	authGroup.Post("/change-email", middleware.Authentication, authHandler.RequestEmailChange)
+	authGroup.Post("/change-email/confirm", middleware.Authentication, authHandler.ConfirmEmailChange)
+	authGroup.Post("/mfa/setup", middleware.Authentication, authHandler.SetupMFA)
+	authGroup.Post("/mfa/enable", middleware.Authentication, authHandler.EnableMFA)
+	authGroup.Post("/mfa/disable", middleware.Authentication, authHandler.DisableMFA)
+	authGroup.Post("/mfa/verify", authHandler.VerifyMFA)
+}
+
+func NewWellKnownHandler(router fiber.Router, authUsecase usecase.AuthUsecaseItf) {
+	authHandler := AuthHandler{
+		authUsecase: authUsecase,
+	}
+
+	router.Get("/.well-known/jwks.json", authHandler.GetJWKS)
 }
 
 func (h *AuthHandler) Register(ctx *fiber.Ctx) error {
@@ -72,7 +95,7 @@ func (h *AuthHandler) VerifyOTP(ctx *fiber.Ctx) error {
 		return res.ErrValidation(validationsErrors)
 	}
 
-	accessToken, refreshToken, err := h.authUsecase.VerifyOTP(*req)
+	accessToken, refreshToken, err := h.authUsecase.VerifyOTP(*req, sessionMetadata(ctx))
 	if err != nil {
 		return err
 	}
@@ -85,6 +108,28 @@ func (h *AuthHandler) VerifyOTP(ctx *fiber.Ctx) error {
 	return res.OK(ctx, payload, res.VerifyOTPSuccess)
 }
 
+func (h *AuthHandler) ResendOTP(ctx *fiber.Ctx) error {
+	req := new(dto.ResendOTPRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrInternalServerError(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationsErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationsErrors)
+	}
+
+	if err := h.authUsecase.ResendOTP(*req); err != nil {
+		return err
+	}
+
+	return res.OK(ctx, nil, res.ResendOTPSuccess)
+}
+
 func (h *AuthHandler) Login(ctx *fiber.Ctx) error {
 	req := new(dto.LoginRequest)
 	if err := ctx.BodyParser(req); err != nil {
@@ -100,14 +145,13 @@ func (h *AuthHandler) Login(ctx *fiber.Ctx) error {
 		return res.ErrValidation(validationsErrors)
 	}
 
-	accessToken, refreshToken, err := h.authUsecase.Login(*req)
+	payload, err := h.authUsecase.Login(*req, sessionMetadata(ctx))
 	if err != nil {
 		return err
 	}
 
-	payload := dto.TokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	if payload.MFARequired {
+		return res.OK(ctx, payload, res.MFARequiredSuccess)
 	}
 
 	return res.OK(ctx, payload, res.LoginSuccess)
@@ -138,15 +182,24 @@ func (h *AuthHandler) GoogleCallback(ctx *fiber.Ctx) error {
 		return res.ErrValidation(validationErrors)
 	}
 
-	accessToken, refreshToken, isNewUser, err := h.authUsecase.GoogleCallback(req)
+	tokens, isNewUser, err := h.authUsecase.GoogleCallback(req, sessionMetadata(ctx))
 	if err != nil {
 		return err
 	}
 
+	if tokens.MFARequired {
+		redirectURL := fmt.Sprintf("%s?mfa_required=true&mfa_token=%s&is_new_user=%t",
+			h.cfg.FERedirectURL,
+			url.QueryEscape(tokens.MFAToken),
+			isNewUser)
+
+		return ctx.Redirect(redirectURL, fiber.StatusSeeOther)
+	}
+
 	redirectURL := fmt.Sprintf("%s?access_token=%s&refresh_token=%s&is_new_user=%t",
 		h.cfg.FERedirectURL,
-		url.QueryEscape(accessToken),
-		url.QueryEscape(refreshToken),
+		url.QueryEscape(tokens.AccessToken),
+		url.QueryEscape(tokens.RefreshToken),
 		isNewUser)
 
 	return ctx.Redirect(redirectURL, fiber.StatusSeeOther)
@@ -167,7 +220,7 @@ func (h *AuthHandler) RefreshToken(ctx *fiber.Ctx) error {
 		return res.ErrValidation(validationsErrors)
 	}
 
-	accessToken, refreshToken, err := h.authUsecase.RefreshToken(*req)
+	accessToken, refreshToken, err := h.authUsecase.RefreshToken(*req, sessionMetadata(ctx))
 	if err != nil {
 		return err
 	}
@@ -195,9 +248,277 @@ func (h *AuthHandler) Logout(ctx *fiber.Ctx) error {
 		return res.ErrValidation(validationsErrors)
 	}
 
-	if err := h.authUsecase.Logout(*req); err != nil {
+	accessToken := ""
+	parts := strings.Fields(ctx.Get(fiber.HeaderAuthorization))
+	if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+		accessToken = parts[1]
+	}
+
+	if err := h.authUsecase.Logout(*req, accessToken); err != nil {
 		return err
 	}
 
 	return res.OK(ctx, nil, res.LogoutSuccess)
 }
+
+func (h *AuthHandler) ForgotPassword(ctx *fiber.Ctx) error {
+	req := new(dto.ForgotPasswordRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrInternalServerError(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationsErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationsErrors)
+	}
+
+	if err := h.authUsecase.ForgotPassword(*req); err != nil {
+		return err
+	}
+
+	return res.OK(ctx, nil, res.ForgotPasswordSuccess)
+}
+
+func (h *AuthHandler) ResetPassword(ctx *fiber.Ctx) error {
+	req := new(dto.ResetPasswordRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrInternalServerError(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationsErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationsErrors)
+	}
+
+	if err := h.authUsecase.ResetPassword(*req, sessionMetadata(ctx)); err != nil {
+		return err
+	}
+
+	return res.OK(ctx, nil, res.ResetPasswordSuccess)
+}
+
+func (h *AuthHandler) GetSessions(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessions, errRes := h.authUsecase.GetSessions(userID)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, sessions)
+}
+
+func (h *AuthHandler) RevokeSession(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	sessionID, parseErr := uuid.Parse(ctx.Params("id"))
+	if parseErr != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if errRes := h.authUsecase.RevokeSession(userID, sessionID); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.RevokeSessionSuccess)
+}
+
+func (h *AuthHandler) LogoutAll(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if errRes := h.authUsecase.LogoutAll(userID); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.LogoutAllSuccess)
+}
+
+func (h *AuthHandler) RequestEmailChange(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.ChangeEmailRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	if errRes := h.authUsecase.RequestEmailChange(userID, *req); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.ChangeEmailSuccess)
+}
+
+func (h *AuthHandler) ConfirmEmailChange(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.ConfirmChangeEmailRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	payload, errRes := h.authUsecase.ConfirmEmailChange(userID, *req, sessionMetadata(ctx))
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, payload, res.ConfirmEmailSuccess)
+}
+
+func (h *AuthHandler) SetupMFA(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	setup, errRes := h.authUsecase.SetupMFA(userID)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, setup, res.MFASetupSuccess)
+}
+
+func (h *AuthHandler) EnableMFA(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.MFACodeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	recoveryCodes, errRes := h.authUsecase.EnableMFA(userID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, recoveryCodes, res.MFAEnableSuccess)
+}
+
+func (h *AuthHandler) DisableMFA(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.MFACodeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	if errRes := h.authUsecase.DisableMFA(userID, *req); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.MFADisableSuccess)
+}
+
+func (h *AuthHandler) VerifyMFA(ctx *fiber.Ctx) error {
+	req := new(dto.VerifyMFARequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrInternalServerError(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationsErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationsErrors)
+	}
+
+	payload, err := h.authUsecase.VerifyMFA(*req, sessionMetadata(ctx))
+	if err != nil {
+		return err
+	}
+
+	return res.OK(ctx, payload, res.LoginSuccess)
+}
+
+func (h *AuthHandler) GetJWKS(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return ctx.JSON(h.authUsecase.GetJWKS())
+}
+
+func sessionMetadata(ctx *fiber.Ctx) dto.SessionMetadata {
+	return dto.SessionMetadata{
+		UserAgent: ctx.Get(fiber.HeaderUserAgent),
+		IPAddress: ctx.IP(),
+	}
+}
+
+func getUserIDFromContext(ctx *fiber.Ctx) (uuid.UUID, *res.Err) {
+	userIDStr := ctx.Locals("user_id")
+	if userIDStr == nil {
+		return uuid.Nil, res.ErrUnauthorized("User not authenticated")
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		return uuid.Nil, res.ErrUnauthorized("Invalid user ID")
+	}
+
+	return userID, nil
+}
@@ -2,9 +2,15 @@ package usecase
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"log"
 	"math/big"
+	"strings"
+	"time"
 
 	"github.com/Ablebil/eco-sample/config"
 	userRepository "github.com/Ablebil/eco-sample/internal/app/user/repository"
@@ -13,19 +19,36 @@ import (
 	"github.com/Ablebil/eco-sample/internal/infra/email"
 	"github.com/Ablebil/eco-sample/internal/infra/jwt"
 	"github.com/Ablebil/eco-sample/internal/infra/oauth"
+	"github.com/Ablebil/eco-sample/internal/infra/postgresql"
 	"github.com/Ablebil/eco-sample/internal/infra/redis"
 	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/infra/totp"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthUsecaseItf interface {
 	Register(req dto.RegisterRequest) *res.Err
-	VerifyOTP(req dto.VerifyOTPRequest) (string, string, *res.Err)
-	Login(req dto.LoginRequest) (string, string, *res.Err)
+	VerifyOTP(req dto.VerifyOTPRequest, meta dto.SessionMetadata) (string, string, *res.Err)
+	Login(req dto.LoginRequest, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err)
 	GoogleLogin() (string, *res.Err)
-	GoogleCallback(req *dto.GoogleCallbackRequest) (string, string, bool, *res.Err)
-	RefreshToken(req dto.RefreshTokenRequest) (string, string, *res.Err)
-	Logout(req dto.LogoutRequest) *res.Err
+	GoogleCallback(req *dto.GoogleCallbackRequest, meta dto.SessionMetadata) (*dto.TokenResponse, bool, *res.Err)
+	RefreshToken(req dto.RefreshTokenRequest, meta dto.SessionMetadata) (string, string, *res.Err)
+	Logout(req dto.LogoutRequest, accessToken string) *res.Err
+	GetSessions(userID uuid.UUID) ([]dto.SessionResponse, *res.Err)
+	RevokeSession(userID, sessionID uuid.UUID) *res.Err
+	LogoutAll(userID uuid.UUID) *res.Err
+	ResendOTP(req dto.ResendOTPRequest) *res.Err
+	RequestEmailChange(userID uuid.UUID, req dto.ChangeEmailRequest) *res.Err
+	ConfirmEmailChange(userID uuid.UUID, req dto.ConfirmChangeEmailRequest, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err)
+	ForgotPassword(req dto.ForgotPasswordRequest) *res.Err
+	ResetPassword(req dto.ResetPasswordRequest, meta dto.SessionMetadata) *res.Err
+	SetupMFA(userID uuid.UUID) (*dto.MFASetupResponse, *res.Err)
+	EnableMFA(userID uuid.UUID, req dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, *res.Err)
+	DisableMFA(userID uuid.UUID, req dto.MFACodeRequest) *res.Err
+	VerifyMFA(req dto.VerifyMFARequest, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err)
+	GetJWKS() jwt.JWKS
+	RevokeAccessTokens(userID uuid.UUID) *res.Err
 }
 
 type AuthUsecase struct {
@@ -35,9 +58,10 @@ type AuthUsecase struct {
 	email          email.EmailItf
 	redis          redis.RedisItf
 	oauth          oauth.OAuthItf
+	totp           totp.TOTPItf
 }
 
-func NewAuthUsecase(userRepository userRepository.UserRepositoryItf, cfg *config.Config, jwt jwt.JWTItf, email email.EmailItf, redis redis.RedisItf, oauth oauth.OAuthItf) AuthUsecaseItf {
+func NewAuthUsecase(userRepository userRepository.UserRepositoryItf, cfg *config.Config, jwt jwt.JWTItf, email email.EmailItf, redis redis.RedisItf, oauth oauth.OAuthItf, totp totp.TOTPItf) AuthUsecaseItf {
 	return &AuthUsecase{
 		userRepository: userRepository,
 		cfg:            cfg,
@@ -45,6 +69,7 @@ func NewAuthUsecase(userRepository userRepository.UserRepositoryItf, cfg *config
 		email:          email,
 		redis:          redis,
 		oauth:          oauth,
+		totp:           totp,
 	}
 }
 
@@ -68,7 +93,7 @@ func (uc *AuthUsecase) Register(req dto.RegisterRequest) *res.Err {
 	hashedPassword := string(hashed)
 
 	if needUpdatePassword {
-		if err := uc.userRepository.UpdateUser(req.Email, &entity.User{
+		if err := uc.userRepository.UpdateUser(user.ID, &entity.User{
 			Password: &hashedPassword,
 		}); err != nil {
 			return res.ErrInternalServerError(res.FailedUpdateUser)
@@ -84,27 +109,14 @@ func (uc *AuthUsecase) Register(req dto.RegisterRequest) *res.Err {
 		}
 	}
 
-	max := big.NewInt(900000)
-	n, randErr := rand.Int(rand.Reader, max)
-	if randErr != nil {
-		return res.ErrInternalServerError(res.FailedGenerateOTP)
-	}
-
-	otp := fmt.Sprintf("%06d", 100000+n.Int64())
-	expiration := uc.cfg.OTPExpiry
-
-	if err := uc.redis.SetOTP(req.Email, otp, expiration); err != nil {
-		return res.ErrInternalServerError(res.FailedStoreOTP)
-	}
+	return uc.sendOTP(redis.OTPPurposeVerifyEmail, req.Email)
+}
 
-	if err := uc.email.SendOTPEmail(req.Email, otp); err != nil {
-		return res.ErrInternalServerError(res.FailedSendOTPEmail)
+func (uc *AuthUsecase) VerifyOTP(req dto.VerifyOTPRequest, meta dto.SessionMetadata) (string, string, *res.Err) {
+	if errRes := uc.checkOTP(redis.OTPPurposeVerifyEmail, req.Email, req.OTP, meta.IPAddress); errRes != nil {
+		return "", "", errRes
 	}
 
-	return nil
-}
-
-func (uc *AuthUsecase) VerifyOTP(req dto.VerifyOTPRequest) (string, string, *res.Err) {
 	user, err := uc.userRepository.GetUserByEmail(req.Email)
 	if err != nil {
 		return "", "", res.ErrInternalServerError(res.FailedFindUser)
@@ -114,82 +126,46 @@ func (uc *AuthUsecase) VerifyOTP(req dto.VerifyOTPRequest) (string, string, *res
 		return "", "", res.ErrNotFound(res.UserNotFound)
 	}
 
-	storedOTP, err := uc.redis.GetOTP(req.Email)
-	if err != nil || storedOTP != req.OTP {
-		return "", "", res.ErrBadRequest(res.InvalidOTP)
-	}
-
-	if err := uc.redis.DeleteOTP(req.Email); err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedDeleteOTP)
-	}
-
-	refreshToken, err := uc.jwt.GenerateRefershToken(user.ID, false)
-	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedGenerateRefreshToken)
-	}
-
-	if err := uc.userRepository.AddRefreshToken(user.ID, refreshToken); err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedAddRefreshToken)
-	}
-
 	user.Verified = true
 
-	if err := uc.userRepository.UpdateUser(req.Email, user); err != nil {
+	if err := uc.userRepository.UpdateUser(user.ID, user); err != nil {
 		return "", "", res.ErrInternalServerError(res.FailedUpdateUser)
 	}
 
-	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email)
-	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedGenerateAccessToken)
-	}
+	meta.DeviceName = req.DeviceName
 
-	return accessToken, refreshToken, nil
+	return uc.createSession(user, false, meta)
 }
 
-func (uc *AuthUsecase) Login(req dto.LoginRequest) (string, string, *res.Err) {
-	user, err := uc.userRepository.GetUserByEmail(req.Email)
-	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedFindUser)
-	}
-
-	if user == nil {
-		return "", "", res.ErrUnauthorized(res.InvalidCredentials)
-	}
+func (uc *AuthUsecase) Login(req dto.LoginRequest, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err) {
+	emailKey := "login:email:" + req.Email
+	ipKey := ipAttemptKey(meta.IPAddress)
 
-	if bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(req.Password)) != nil {
-		return "", "", res.ErrUnauthorized(res.InvalidCredentials)
+	if errRes := uc.checkLock(emailKey, ipKey); errRes != nil {
+		return nil, errRes
 	}
 
-	if !user.Verified {
-		return "", "", res.ErrUnauthorized(res.UserNotVerified)
-	}
-
-	refreshToken, err := uc.jwt.GenerateRefershToken(user.ID, false)
+	user, err := uc.userRepository.GetUserByEmail(req.Email)
 	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedGenerateRefreshToken)
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
 	}
 
-	refreshTokens, err := uc.userRepository.GetRefreshTokens(user.ID)
-	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedGetRefreshTokens)
+	if user == nil || user.Password == nil ||
+		bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(req.Password)) != nil {
+		return nil, uc.loginFailure(emailKey, ipKey)
 	}
 
-	if len(refreshTokens) >= 2 {
-		if err := uc.userRepository.RemoveRefreshToken(refreshTokens[0].Token); err != nil {
-			return "", "", res.ErrInternalServerError(res.FailedRemoveRefreshToken)
-		}
+	if err := uc.redis.DeleteAttempts(emailKey); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedTrackAttempts)
 	}
 
-	if err := uc.userRepository.AddRefreshToken(user.ID, refreshToken); err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedAddRefreshToken)
+	if !user.Verified {
+		return nil, res.ErrUnauthorized(res.UserNotVerified)
 	}
 
-	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email)
-	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedGenerateAccessToken)
-	}
+	meta.DeviceName = req.DeviceName
 
-	return accessToken, refreshToken, nil
+	return uc.completeLogin(user, req.RememberMe, meta)
 }
 
 func (uc *AuthUsecase) GoogleLogin() (string, *res.Err) {
@@ -216,47 +192,47 @@ func (uc *AuthUsecase) GoogleLogin() (string, *res.Err) {
 	return url, nil
 }
 
-func (uc *AuthUsecase) GoogleCallback(req *dto.GoogleCallbackRequest) (string, string, bool, *res.Err) {
+func (uc *AuthUsecase) GoogleCallback(req *dto.GoogleCallbackRequest, meta dto.SessionMetadata) (*dto.TokenResponse, bool, *res.Err) {
 	if req.Error != "" {
-		return "", "", false, res.ErrInternalServerError(res.FailedOAuthCallback)
+		return nil, false, res.ErrInternalServerError(res.FailedOAuthCallback)
 	}
 
 	state, err := uc.redis.GetOAuthState(req.State)
 	if err != nil {
-		return "", "", false, res.ErrUnauthorized(res.OAuthStateNotFound)
+		return nil, false, res.ErrUnauthorized(res.OAuthStateNotFound)
 	}
 
 	if string(state) != req.State {
-		return "", "", false, res.ErrUnauthorized(res.OAuthStateInvalid)
+		return nil, false, res.ErrUnauthorized(res.OAuthStateInvalid)
 	}
 
 	if err := uc.redis.DeleteOAuthState(req.State); err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedDeleteOAuthState)
+		return nil, false, res.ErrInternalServerError(res.FailedDeleteOAuthState)
 	}
 
 	token, err := uc.oauth.ExchangeToken(req.Code)
 	if err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedExchangeOAuthToken)
+		return nil, false, res.ErrInternalServerError(res.FailedExchangeOAuthToken)
 	}
 
 	profile, err := uc.oauth.GetProfile(token)
 	if err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedGetOAuthProfile)
+		return nil, false, res.ErrInternalServerError(res.FailedGetOAuthProfile)
 	}
 
 	isNewUser := false
 
 	user, err := uc.userRepository.GetUserByEmail(profile.Email)
 	if err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedFindUser)
+		return nil, false, res.ErrInternalServerError(res.FailedFindUser)
 	}
 
 	if user != nil {
 		if user.GoogleID == nil {
-			if err := uc.userRepository.UpdateUser(user.Email, &entity.User{
+			if err := uc.userRepository.UpdateUser(user.ID, &entity.User{
 				GoogleID: &profile.ID,
 			}); err != nil {
-				return "", "", false, res.ErrInternalServerError(res.FailedUpdateUser)
+				return nil, false, res.ErrInternalServerError(res.FailedUpdateUser)
 			}
 		}
 	} else {
@@ -270,90 +246,967 @@ func (uc *AuthUsecase) GoogleCallback(req *dto.GoogleCallbackRequest) (string, s
 		}
 
 		if err := uc.userRepository.CreateUser(user); err != nil {
-			return "", "", false, res.ErrInternalServerError(res.FailedCreateUser)
+			return nil, false, res.ErrInternalServerError(res.FailedCreateUser)
 		}
 	}
 
 	if !user.Verified {
-		return "", "", false, res.ErrUnauthorized(res.UserNotVerified)
+		return nil, false, res.ErrUnauthorized(res.UserNotVerified)
+	}
+
+	tokens, errRes := uc.completeLogin(user, false, meta)
+	if errRes != nil {
+		return nil, false, errRes
+	}
+
+	return tokens, isNewUser, nil
+}
+
+func (uc *AuthUsecase) RefreshToken(req dto.RefreshTokenRequest, meta dto.SessionMetadata) (string, string, *res.Err) {
+	session, err := uc.userRepository.GetRefreshToken(req.RefreshToken)
+	if err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if session == nil || session.User == nil {
+		return "", "", res.ErrUnauthorized(res.InvalidRefreshToken)
+	}
+
+	if session.ConsumedAt != nil {
+		return "", "", uc.revokeReusedFamily(session, meta)
+	}
+
+	_, rememberMe, err := uc.jwt.VerifyRefreshToken(req.RefreshToken)
+	if err != nil {
+		return "", "", res.ErrUnauthorized(res.InvalidRefreshToken)
+	}
+
+	consumed, err := uc.userRepository.ConsumeRefreshToken(session.ID)
+	if err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+	}
+
+	if !consumed {
+		return "", "", uc.revokeReusedFamily(session, meta)
+	}
+
+	user := session.User
+
+	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email, string(user.Role))
+	if err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedGenerateAccessToken)
+	}
+
+	refreshToken, err := uc.jwt.GenerateRefershToken(user.ID, rememberMe)
+	if err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedGenerateRefreshToken)
+	}
+
+	now := time.Now()
+	if err := uc.userRepository.AddRefreshToken(&entity.RefreshToken{
+		UserID:     user.ID,
+		FamilyID:   session.FamilyID,
+		Token:      refreshToken,
+		DeviceName: session.DeviceName,
+		UserAgent:  optionalString(meta.UserAgent),
+		IPAddress:  optionalString(meta.IPAddress),
+		LastUsedAt: &now,
+	}); err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedAddRefreshToken)
+	}
+
+	if err := uc.userRepository.RemoveConsumedRefreshTokens(session.FamilyID, now.Add(-uc.cfg.RememberMeRefreshTokenExpiry)); err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+func (uc *AuthUsecase) Logout(req dto.LogoutRequest, accessToken string) *res.Err {
+	session, err := uc.userRepository.GetRefreshToken(req.RefreshToken)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if session == nil || session.ConsumedAt != nil {
+		return res.ErrUnauthorized(res.InvalidRefreshToken)
+	}
+
+	if err := uc.userRepository.RemoveRefreshTokenFamily(session.FamilyID); err != nil {
+		return res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+	}
+
+	if accessToken == "" {
+		return nil
+	}
+
+	claims, err := uc.jwt.VerifyAccessToken(accessToken)
+	if err != nil || claims.UserID != session.UserID || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+
+	if err := uc.redis.DenyAccessToken(claims.ID, time.Until(claims.ExpiresAt.Time)); err != nil {
+		return res.ErrInternalServerError(res.FailedRevokeAccessToken)
+	}
+
+	return nil
+}
+
+func (uc *AuthUsecase) ResendOTP(req dto.ResendOTPRequest) *res.Err {
+	purpose := redis.OTPPurpose(req.Purpose)
+	if purpose == redis.OTPPurposeResetPassword {
+		return uc.sendResetPasswordOTP(req.Email)
+	}
+
+	user, err := uc.userRepository.GetUserByEmail(req.Email)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return res.ErrNotFound(res.UserNotFound)
+	}
+
+	if purpose == redis.OTPPurposeVerifyEmail && user.Verified {
+		return res.ErrBadRequest(res.UserAlreadyVerified)
+	}
+
+	return uc.sendOTP(purpose, req.Email)
+}
+
+func (uc *AuthUsecase) RequestEmailChange(userID uuid.UUID, req dto.ChangeEmailRequest) *res.Err {
+	user, err := uc.userRepository.GetUserByID(userID)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return res.ErrNotFound(res.UserNotFound)
+	}
+
+	if user.Password != nil && bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(req.Password)) != nil {
+		return res.ErrUnauthorized(res.InvalidCurrentPassword)
+	}
+
+	if strings.EqualFold(user.Email, req.NewEmail) {
+		return res.ErrBadRequest(res.SameEmail)
+	}
+
+	existing, err := uc.userRepository.GetUserByEmail(req.NewEmail)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if existing != nil {
+		return res.ErrConflict(res.EmailAlreadyExists)
+	}
+
+	if errRes := uc.sendOTP(redis.OTPPurposeChangeEmail, req.NewEmail); errRes != nil {
+		return errRes
+	}
+
+	if err := uc.redis.SetPendingEmail(user.ID.String(), req.NewEmail, uc.cfg.OTPExpiry); err != nil {
+		return res.ErrInternalServerError(res.FailedStorePendingEmail)
 	}
 
-	refreshToken, err := uc.jwt.GenerateRefershToken(user.ID, false)
+	if err := uc.email.SendEmailChangeNotice(user.Email, req.NewEmail); err != nil {
+		return res.ErrInternalServerError(res.FailedSendNoticeEmail)
+	}
+
+	return nil
+}
+
+func (uc *AuthUsecase) ConfirmEmailChange(userID uuid.UUID, req dto.ConfirmChangeEmailRequest, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err) {
+	user, err := uc.userRepository.GetUserByID(userID)
 	if err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedGenerateRefreshToken)
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, res.ErrNotFound(res.UserNotFound)
 	}
 
-	refreshTokens, err := uc.userRepository.GetRefreshTokens(user.ID)
+	newEmail, err := uc.redis.GetPendingEmail(user.ID.String())
 	if err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedGetRefreshTokens)
+		return nil, res.ErrInternalServerError(res.FailedStorePendingEmail)
 	}
 
-	if len(refreshTokens) >= 2 {
-		if err := uc.userRepository.RemoveRefreshToken(refreshTokens[0].Token); err != nil {
-			return "", "", false, res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+	if newEmail == "" {
+		return nil, res.ErrBadRequest(res.NoPendingEmail)
+	}
+
+	if errRes := uc.checkOTP(redis.OTPPurposeChangeEmail, newEmail, req.OTP, meta.IPAddress); errRes != nil {
+		return nil, errRes
+	}
+
+	if err := uc.userRepository.UpdateUserEmail(user.ID, newEmail); err != nil {
+		if postgresql.CheckError(err, postgresql.ErrUniqueViolation) {
+			return nil, res.ErrConflict(res.EmailAlreadyExists)
 		}
+
+		return nil, res.ErrInternalServerError(res.FailedUpdateUser)
+	}
+
+	if err := uc.redis.DeletePendingEmail(user.ID.String()); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedStorePendingEmail)
 	}
 
-	if err := uc.userRepository.AddRefreshToken(user.ID, refreshToken); err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedAddRefreshToken)
+	if errRes := uc.RevokeAccessTokens(user.ID); errRes != nil {
+		return nil, errRes
 	}
 
-	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email)
+	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, newEmail, string(user.Role))
 	if err != nil {
-		return "", "", false, res.ErrInternalServerError(res.FailedGenerateAccessToken)
+		return nil, res.ErrInternalServerError(res.FailedGenerateAccessToken)
 	}
 
-	return accessToken, refreshToken, isNewUser, nil
+	return &dto.TokenResponse{AccessToken: accessToken}, nil
 }
 
-func (uc *AuthUsecase) RefreshToken(req dto.RefreshTokenRequest) (string, string, *res.Err) {
-	user, err := uc.userRepository.GetUserByRefreshToken(req.RefreshToken)
+func (uc *AuthUsecase) ForgotPassword(req dto.ForgotPasswordRequest) *res.Err {
+	return uc.sendResetPasswordOTP(req.Email)
+}
+
+func (uc *AuthUsecase) ResetPassword(req dto.ResetPasswordRequest, meta dto.SessionMetadata) *res.Err {
+	if errRes := uc.checkOTP(redis.OTPPurposeResetPassword, req.Email, req.OTP, meta.IPAddress); errRes != nil {
+		return errRes
+	}
+
+	user, err := uc.userRepository.GetUserByEmail(req.Email)
 	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedFindUser)
+		return res.ErrInternalServerError(res.FailedFindUser)
 	}
 
 	if user == nil {
-		return "", "", res.ErrUnauthorized(res.InvalidRefreshToken)
+		return res.ErrBadRequest(res.InvalidOTP)
 	}
 
-	if _, err := uc.jwt.VerifyRefreshToken(req.RefreshToken); err != nil {
-		return "", "", res.ErrUnauthorized(res.InvalidRefreshToken)
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedHashPassword)
+	}
+
+	hashedPassword := string(hashed)
+
+	if err := uc.userRepository.UpdateUser(user.ID, &entity.User{
+		Password: &hashedPassword,
+	}); err != nil {
+		return res.ErrInternalServerError(res.FailedUpdateUser)
+	}
+
+	if err := uc.userRepository.RemoveRefreshTokensByUserID(user.ID); err != nil {
+		return res.ErrInternalServerError(res.FailedRevokeSessions)
 	}
 
-	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email)
+	return uc.RevokeAccessTokens(user.ID)
+}
+
+type mfaChallenge struct {
+	UserID     uuid.UUID `json:"user_id"`
+	RememberMe bool      `json:"remember_me"`
+	DeviceName string    `json:"device_name"`
+}
+
+const recoveryCodeCount = 10
+
+func generateMFAToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, 5)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(hex.EncodeToString(bytes))
+	return code[:5] + "-" + code[5:], nil
+}
+
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(900000))
 	if err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedGenerateAccessToken)
+		return "", err
+	}
+
+	return fmt.Sprintf("%06d", 100000+n.Int64()), nil
+}
+
+func (uc *AuthUsecase) GetSessions(userID uuid.UUID) ([]dto.SessionResponse, *res.Err) {
+	refreshTokens, err := uc.userRepository.GetRefreshTokens(userID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetSessions)
+	}
+
+	response := make([]dto.SessionResponse, 0, len(refreshTokens))
+	for i := len(refreshTokens) - 1; i >= 0; i-- {
+		refreshToken := refreshTokens[i]
+		response = append(response, dto.SessionResponse{
+			ID:         refreshToken.ID,
+			DeviceName: refreshToken.DeviceName,
+			UserAgent:  refreshToken.UserAgent,
+			IPAddress:  refreshToken.IPAddress,
+			LastUsedAt: refreshToken.LastUsedAt,
+			CreatedAt:  refreshToken.CreatedAt,
+		})
+	}
+
+	return response, nil
+}
+
+func (uc *AuthUsecase) RevokeSession(userID, sessionID uuid.UUID) *res.Err {
+	session, err := uc.userRepository.GetRefreshTokenByID(sessionID)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGetSessions)
+	}
+
+	if session == nil || session.UserID != userID || session.ConsumedAt != nil {
+		return res.ErrNotFound(res.SessionNotFound)
+	}
+
+	if err := uc.userRepository.RemoveRefreshTokenFamily(session.FamilyID); err != nil {
+		return res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+	}
+
+	return nil
+}
+
+func (uc *AuthUsecase) LogoutAll(userID uuid.UUID) *res.Err {
+	if err := uc.userRepository.RemoveRefreshTokensByUserID(userID); err != nil {
+		return res.ErrInternalServerError(res.FailedRevokeSessions)
+	}
+
+	return uc.RevokeAccessTokens(userID)
+}
+
+func (uc *AuthUsecase) SetupMFA(userID uuid.UUID) (*dto.MFASetupResponse, *res.Err) {
+	user, err := uc.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, res.ErrNotFound(res.UserNotFound)
+	}
+
+	if user.MFAEnabled {
+		return nil, res.ErrConflict(res.MFAAlreadyEnabled)
+	}
+
+	if uc.totp == nil {
+		return nil, res.ErrServiceUnavailable(res.MFAUnavailable)
+	}
+
+	secret, err := uc.totp.GenerateSecret()
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGenerateMFASecret)
+	}
+
+	encryptedSecret, err := uc.totp.EncryptSecret(secret)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGenerateMFASecret)
 	}
 
-	refreshToken, err := uc.jwt.GenerateRefershToken(user.ID, false)
+	if err := uc.userRepository.UpdateUserMFA(user.ID, &encryptedSecret, false); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUpdateMFA)
+	}
+
+	return &dto.MFASetupResponse{
+		Secret:     secret,
+		OTPAuthURI: uc.totp.GenerateURI(secret, user.Email),
+	}, nil
+}
+
+func (uc *AuthUsecase) EnableMFA(userID uuid.UUID, req dto.MFACodeRequest) (*dto.MFARecoveryCodesResponse, *res.Err) {
+	user, err := uc.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, res.ErrNotFound(res.UserNotFound)
+	}
+
+	if user.MFAEnabled {
+		return nil, res.ErrConflict(res.MFAAlreadyEnabled)
+	}
+
+	if user.TOTPSecret == nil {
+		return nil, res.ErrBadRequest(res.MFASetupRequired)
+	}
+
+	if uc.totp == nil {
+		return nil, res.ErrServiceUnavailable(res.MFAUnavailable)
+	}
+
+	valid, errRes := uc.checkTOTP(user, req.Code)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if !valid {
+		return nil, res.ErrBadRequest(res.InvalidMFACode)
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, res.ErrInternalServerError(res.FailedGenerateRecovery)
+		}
+
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	if err := uc.userRepository.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGenerateRecovery)
+	}
+
+	if err := uc.userRepository.UpdateUserMFA(user.ID, user.TOTPSecret, true); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUpdateMFA)
+	}
+
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (uc *AuthUsecase) DisableMFA(userID uuid.UUID, req dto.MFACodeRequest) *res.Err {
+	user, err := uc.userRepository.GetUserByID(userID)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return res.ErrNotFound(res.UserNotFound)
+	}
+
+	if !user.MFAEnabled {
+		return res.ErrBadRequest(res.MFANotEnabled)
+	}
+
+	valid, errRes := uc.checkMFACode(user, req.Code)
+	if errRes != nil {
+		return errRes
+	}
+
+	if !valid {
+		return res.ErrBadRequest(res.InvalidMFACode)
+	}
+
+	if err := uc.userRepository.UpdateUserMFA(user.ID, nil, false); err != nil {
+		return res.ErrInternalServerError(res.FailedUpdateMFA)
+	}
+
+	if err := uc.userRepository.RemoveRecoveryCodes(user.ID); err != nil {
+		return res.ErrInternalServerError(res.FailedUpdateMFA)
+	}
+
+	return nil
+}
+
+func (uc *AuthUsecase) VerifyMFA(req dto.VerifyMFARequest, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err) {
+	value, err := uc.redis.GetMFAChallenge(req.MFAToken)
+	if err != nil || value == nil {
+		return nil, res.ErrUnauthorized(res.InvalidMFAToken)
+	}
+
+	var challenge mfaChallenge
+	if err := json.Unmarshal(value, &challenge); err != nil {
+		return nil, res.ErrUnauthorized(res.InvalidMFAToken)
+	}
+
+	user, err := uc.userRepository.GetUserByID(challenge.UserID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil || !user.MFAEnabled {
+		return nil, res.ErrUnauthorized(res.InvalidMFAToken)
+	}
+
+	attemptKey := "mfa:user:" + user.ID.String()
+	if errRes := uc.checkLock(attemptKey); errRes != nil {
+		return nil, errRes
+	}
+
+	valid, errRes := uc.checkMFACode(user, req.Code)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	if !valid {
+		locked, errRes := uc.registerFailure(attemptKey, uc.cfg.MaxOTPAttempts)
+		if errRes != nil {
+			return nil, errRes
+		}
+
+		if locked != nil {
+			if err := uc.redis.DeleteMFAChallenge(req.MFAToken); err != nil {
+				return nil, res.ErrInternalServerError(res.FailedDeleteMFAChallenge)
+			}
+
+			return nil, locked
+		}
+
+		return nil, res.ErrUnauthorized(res.InvalidMFACode)
+	}
+
+	if err := uc.redis.DeleteAttempts(attemptKey); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedTrackAttempts)
+	}
+
+	if err := uc.redis.DeleteMFAChallenge(req.MFAToken); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedDeleteMFAChallenge)
+	}
+
+	meta.DeviceName = challenge.DeviceName
+
+	accessToken, refreshToken, errRes := uc.createSession(user, challenge.RememberMe, meta)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (uc *AuthUsecase) GetJWKS() jwt.JWKS {
+	return uc.jwt.JWKS()
+}
+
+func (uc *AuthUsecase) completeLogin(user *entity.User, rememberMe bool, meta dto.SessionMetadata) (*dto.TokenResponse, *res.Err) {
+	if user.MFAEnabled {
+		token, err := generateMFAToken()
+		if err != nil {
+			return nil, res.ErrInternalServerError(res.FailedStoreMFAChallenge)
+		}
+
+		value, err := json.Marshal(mfaChallenge{
+			UserID:     user.ID,
+			RememberMe: rememberMe,
+			DeviceName: meta.DeviceName,
+		})
+		if err != nil {
+			return nil, res.ErrInternalServerError(res.FailedStoreMFAChallenge)
+		}
+
+		if err := uc.redis.SetMFAChallenge(token, value, uc.cfg.MFAChallengeExpiry); err != nil {
+			return nil, res.ErrInternalServerError(res.FailedStoreMFAChallenge)
+		}
+
+		return &dto.TokenResponse{
+			MFARequired: true,
+			MFAToken:    token,
+		}, nil
+	}
+
+	accessToken, refreshToken, errRes := uc.createSession(user, rememberMe, meta)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return &dto.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (uc *AuthUsecase) checkMFACode(user *entity.User, code string) (bool, *res.Err) {
+	valid, errRes := uc.checkTOTP(user, code)
+	if errRes != nil || valid {
+		return valid, errRes
+	}
+
+	used, err := uc.userRepository.UseRecoveryCode(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return false, res.ErrInternalServerError(res.FailedVerifyMFA)
+	}
+
+	return used, nil
+}
+
+// checkTOTP validates code against the user's secret and records the matched
+// time step, so the same code is rejected if it is presented again. Without an
+// encryption key only recovery codes are accepted.
+func (uc *AuthUsecase) checkTOTP(user *entity.User, code string) (bool, *res.Err) {
+	if user.TOTPSecret == nil || uc.totp == nil {
+		return false, nil
+	}
+
+	secret, err := uc.totp.DecryptSecret(*user.TOTPSecret)
+	if err != nil {
+		return false, res.ErrInternalServerError(res.FailedVerifyMFA)
+	}
+
+	step, ok := uc.totp.Validate(secret, code, user.TOTPLastStep)
+	if !ok {
+		return false, nil
+	}
+
+	used, err := uc.userRepository.UseTOTPStep(user.ID, step)
+	if err != nil {
+		return false, res.ErrInternalServerError(res.FailedVerifyMFA)
+	}
+
+	return used, nil
+}
+
+func (uc *AuthUsecase) sendOTP(purpose redis.OTPPurpose, email string) *res.Err {
+	if errRes := uc.throttleOTP(purpose, email); errRes != nil {
+		return errRes
+	}
+
+	return uc.deliverOTP(purpose, email)
+}
+
+// sendResetPasswordOTP applies the resend cooldown and daily cap to the email
+// whether or not it is registered, and only logs delivery failures, so the
+// response is the same for every address.
+func (uc *AuthUsecase) sendResetPasswordOTP(email string) *res.Err {
+	if errRes := uc.throttleOTP(redis.OTPPurposeResetPassword, email); errRes != nil {
+		return errRes
+	}
+
+	user, err := uc.userRepository.GetUserByEmail(email)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil
+	}
+
+	if errRes := uc.deliverOTP(redis.OTPPurposeResetPassword, email); errRes != nil {
+		log.Printf("failed to send password reset code: %s", errRes.Message)
+	}
+
+	return nil
+}
+
+// throttleOTP enforces the resend cooldown and daily cap for email and starts
+// a new cooldown.
+func (uc *AuthUsecase) throttleOTP(purpose redis.OTPPurpose, email string) *res.Err {
+	cooldown, err := uc.redis.GetOTPCooldownTTL(purpose, email)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedStoreOTP)
+	}
+
+	if cooldown > 0 {
+		return tooManyRequests(res.OTPResendCooldown, cooldown)
+	}
+
+	sent, err := uc.redis.IncrementOTPDailyCount(email)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedStoreOTP)
+	}
+
+	if uc.cfg.OTPDailyLimit > 0 && sent > int64(uc.cfg.OTPDailyLimit) {
+		return res.ErrTooManyRequests(res.OTPDailyLimit)
+	}
+
+	if err := uc.redis.SetOTPCooldown(purpose, email, uc.cfg.OTPResendCooldown); err != nil {
+		return res.ErrInternalServerError(res.FailedStoreOTP)
+	}
+
+	return nil
+}
+
+func (uc *AuthUsecase) deliverOTP(purpose redis.OTPPurpose, email string) *res.Err {
+	otp, err := generateOTP()
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGenerateOTP)
+	}
+
+	if err := uc.redis.SetOTP(purpose, email, otp, uc.cfg.OTPExpiry); err != nil {
+		return res.ErrInternalServerError(res.FailedStoreOTP)
+	}
+
+	switch purpose {
+	case redis.OTPPurposeResetPassword:
+		if err := uc.email.SendResetPasswordEmail(email, otp); err != nil {
+			return res.ErrInternalServerError(res.FailedSendResetEmail)
+		}
+	case redis.OTPPurposeChangeEmail:
+		if err := uc.email.SendChangeEmailOTP(email, otp); err != nil {
+			return res.ErrInternalServerError(res.FailedSendOTPEmail)
+		}
+	default:
+		if err := uc.email.SendOTPEmail(email, otp); err != nil {
+			return res.ErrInternalServerError(res.FailedSendOTPEmail)
+		}
+	}
+
+	return nil
+}
+
+// checkOTP verifies a one-time code. Failures count against the email and,
+// when ipAddress is set, against the same per-IP budget as login so a single
+// client cannot spread guesses across many accounts.
+func (uc *AuthUsecase) checkOTP(purpose redis.OTPPurpose, email, otp, ipAddress string) *res.Err {
+	emailKey := "otp:" + string(purpose) + ":" + email
+	keys := []string{emailKey}
+
+	ipKey := ""
+	if ipAddress != "" {
+		ipKey = ipAttemptKey(ipAddress)
+		keys = append(keys, ipKey)
+	}
+
+	if errRes := uc.checkLock(keys...); errRes != nil {
+		return errRes
+	}
+
+	storedOTP, err := uc.redis.GetOTP(purpose, email)
+	if err != nil || storedOTP == "" || storedOTP != otp {
+		return uc.otpFailure(purpose, email, emailKey, ipKey)
+	}
+
+	if err := uc.redis.DeleteOTP(purpose, email); err != nil {
+		return res.ErrInternalServerError(res.FailedDeleteOTP)
+	}
+
+	if err := uc.redis.DeleteAttempts(emailKey); err != nil {
+		return res.ErrInternalServerError(res.FailedTrackAttempts)
+	}
+
+	return nil
+}
+
+func (uc *AuthUsecase) otpFailure(purpose redis.OTPPurpose, email, emailKey, ipKey string) *res.Err {
+	locked, errRes := uc.registerFailure(emailKey, uc.cfg.MaxOTPAttempts)
+	if errRes != nil {
+		return errRes
+	}
+
+	if locked != nil {
+		if err := uc.redis.DeleteOTP(purpose, email); err != nil {
+			return res.ErrInternalServerError(res.FailedDeleteOTP)
+		}
+
+		return locked
+	}
+
+	if ipKey != "" {
+		locked, errRes = uc.registerFailure(ipKey, uc.cfg.MaxLoginAttemptsPerIP)
+		if errRes != nil {
+			return errRes
+		}
+
+		if locked != nil {
+			return locked
+		}
+	}
+
+	return res.ErrBadRequest(res.InvalidOTP)
+}
+
+func (uc *AuthUsecase) loginFailure(emailKey, ipKey string) *res.Err {
+	locked, errRes := uc.registerFailure(emailKey, uc.cfg.MaxLoginAttempts)
+	if errRes != nil {
+		return errRes
+	}
+
+	if locked != nil {
+		return locked
+	}
+
+	locked, errRes = uc.registerFailure(ipKey, uc.cfg.MaxLoginAttemptsPerIP)
+	if errRes != nil {
+		return errRes
+	}
+
+	if locked != nil {
+		return locked
+	}
+
+	return res.ErrUnauthorized(res.InvalidCredentials)
+}
+
+func (uc *AuthUsecase) checkLock(keys ...string) *res.Err {
+	for _, key := range keys {
+		ttl, err := uc.redis.GetLockTTL(key)
+		if err != nil {
+			return res.ErrInternalServerError(res.FailedTrackAttempts)
+		}
+
+		if ttl > 0 {
+			return tooManyAttempts(ttl)
+		}
+	}
+
+	return nil
+}
+
+// registerFailure counts a failed attempt for key and, once max is reached,
+// locks the key for a duration that doubles with every lockout inside
+// LockoutResetWindow. The returned lock error is nil while attempts remain.
+func (uc *AuthUsecase) registerFailure(key string, max int) (*res.Err, *res.Err) {
+	attempts, err := uc.redis.IncrementAttempts(key, uc.cfg.AttemptWindow)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedTrackAttempts)
+	}
+
+	if max <= 0 || attempts < int64(max) {
+		return nil, nil
+	}
+
+	lockCount, err := uc.redis.IncrementLockCount(key, uc.cfg.LockoutResetWindow)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedTrackAttempts)
+	}
+
+	duration := uc.cfg.LockoutBaseDuration
+	for i := int64(1); i < lockCount && duration < uc.cfg.LockoutMaxDuration; i++ {
+		duration *= 2
+	}
+
+	if duration > uc.cfg.LockoutMaxDuration {
+		duration = uc.cfg.LockoutMaxDuration
+	}
+
+	if err := uc.redis.SetLock(key, duration); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedTrackAttempts)
+	}
+
+	if err := uc.redis.DeleteAttempts(key); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedTrackAttempts)
+	}
+
+	return tooManyAttempts(duration), nil
+}
+
+func (uc *AuthUsecase) createSession(user *entity.User, rememberMe bool, meta dto.SessionMetadata) (string, string, *res.Err) {
+	if errRes := uc.enforceSessionLimit(user.ID); errRes != nil {
+		return "", "", errRes
+	}
+
+	refreshToken, err := uc.jwt.GenerateRefershToken(user.ID, rememberMe)
 	if err != nil {
 		return "", "", res.ErrInternalServerError(res.FailedGenerateRefreshToken)
 	}
 
-	if err := uc.userRepository.RemoveRefreshToken(req.RefreshToken); err != nil {
-		return "", "", res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+	familyID, err := uuid.NewV7()
+	if err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedGenerateRefreshToken)
 	}
 
-	if err := uc.userRepository.AddRefreshToken(user.ID, refreshToken); err != nil {
+	now := time.Now()
+	if err := uc.userRepository.AddRefreshToken(&entity.RefreshToken{
+		UserID:     user.ID,
+		FamilyID:   familyID,
+		Token:      refreshToken,
+		DeviceName: optionalString(meta.DeviceName),
+		UserAgent:  optionalString(meta.UserAgent),
+		IPAddress:  optionalString(meta.IPAddress),
+		LastUsedAt: &now,
+	}); err != nil {
 		return "", "", res.ErrInternalServerError(res.FailedAddRefreshToken)
 	}
 
+	accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email, string(user.Role))
+	if err != nil {
+		return "", "", res.ErrInternalServerError(res.FailedGenerateAccessToken)
+	}
+
 	return accessToken, refreshToken, nil
 }
 
-func (uc *AuthUsecase) Logout(req dto.LogoutRequest) *res.Err {
-	user, err := uc.userRepository.GetUserByRefreshToken(req.RefreshToken)
+func (uc *AuthUsecase) enforceSessionLimit(userID uuid.UUID) *res.Err {
+	if uc.cfg.MaxSessions <= 0 {
+		return nil
+	}
+
+	refreshTokens, err := uc.userRepository.GetRefreshTokens(userID)
 	if err != nil {
-		return res.ErrInternalServerError(res.FailedFindUser)
+		return res.ErrInternalServerError(res.FailedGetRefreshTokens)
 	}
 
-	if user == nil {
-		return res.ErrUnauthorized(res.InvalidRefreshToken)
+	excess := len(refreshTokens) - uc.cfg.MaxSessions + 1
+	if excess <= 0 {
+		return nil
+	}
+
+	if uc.cfg.SessionLimitPolicy == config.SessionPolicyReject {
+		return res.ErrForbidden(res.SessionLimitReached)
+	}
+
+	for _, refreshToken := range refreshTokens[:excess] {
+		if err := uc.userRepository.RemoveRefreshTokenFamily(refreshToken.FamilyID); err != nil {
+			return res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+		}
 	}
 
-	if err := uc.userRepository.RemoveRefreshToken(req.RefreshToken); err != nil {
+	return nil
+}
+
+func (uc *AuthUsecase) revokeReusedFamily(session *entity.RefreshToken, meta dto.SessionMetadata) *res.Err {
+	if err := uc.userRepository.RemoveRefreshTokenFamily(session.FamilyID); err != nil {
 		return res.ErrInternalServerError(res.FailedRemoveRefreshToken)
 	}
 
+	details := fmt.Sprintf("refresh token family %s revoked after a consumed token was presented", session.FamilyID)
+	if err := uc.userRepository.CreateSecurityEvent(&entity.SecurityEvent{
+		UserID:    session.UserID,
+		Type:      entity.SecurityEventRefreshTokenReuse,
+		IPAddress: optionalString(meta.IPAddress),
+		UserAgent: optionalString(meta.UserAgent),
+		Details:   &details,
+	}); err != nil {
+		return res.ErrInternalServerError(res.FailedRecordSecurityEvent)
+	}
+
+	if errRes := uc.RevokeAccessTokens(session.UserID); errRes != nil {
+		return errRes
+	}
+
+	return res.ErrUnauthorized(res.RefreshTokenReused)
+}
+
+// RevokeAccessTokens invalidates every access token issued to the user up to
+// now. The watermark only needs to outlive the longest access token.
+func (uc *AuthUsecase) RevokeAccessTokens(userID uuid.UUID) *res.Err {
+	if err := uc.redis.SetTokensValidAfter(userID.String(), time.Now(), uc.cfg.AccessTokenExpiry); err != nil {
+		return res.ErrInternalServerError(res.FailedRevokeAccessToken)
+	}
+
 	return nil
 }
+
+func ipAttemptKey(ipAddress string) string {
+	return "auth:ip:" + ipAddress
+}
+
+func tooManyAttempts(retryAfter time.Duration) *res.Err {
+	return tooManyRequests(res.TooManyAttempts, retryAfter)
+}
+
+func tooManyRequests(message string, retryAfter time.Duration) *res.Err {
+	errRes := res.ErrTooManyRequests(message)
+	errRes.Payload = map[string]interface{}{
+		"retry_after": int(retryAfter.Seconds()),
+	}
+
+	return errRes
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
@@ -3,6 +3,7 @@ package rest
 import (
 	"github.com/Ablebil/eco-sample/internal/app/challenge/usecase"
 	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
 	res "github.com/Ablebil/eco-sample/internal/infra/response"
 	"github.com/Ablebil/eco-sample/internal/middleware"
 	"github.com/go-playground/validator/v10"
@@ -15,7 +16,7 @@ type ChallengeHandler struct {
 	challengeUsecase usecase.ChallengeUsecaseItf
 }
 
-func NewChallengeHandler(challengeGroup fiber.Router, validator *validator.Validate, challengeUsecase usecase.ChallengeUsecaseItf, middleware middleware.MiddlewareItf) {
+func NewChallengeHandler(challengeGroup, adminGroup, moderationGroup fiber.Router, validator *validator.Validate, challengeUsecase usecase.ChallengeUsecaseItf, middleware middleware.MiddlewareItf) {
 	challengeHandler := ChallengeHandler{
 		validator:        validator,
 		challengeUsecase: challengeUsecase,
@@ -25,9 +26,32 @@ func NewChallengeHandler(challengeGroup fiber.Router, validator *validator.Valid
 	challengeGroup.Get("/", middleware.Authentication, challengeHandler.GetChallenges)
 	challengeGroup.Post("/take", middleware.Authentication, challengeHandler.TakeChallenge)
 	challengeGroup.Post("/complete", middleware.Authentication, challengeHandler.CompleteChallenge)
+	challengeGroup.Post("/abandon", middleware.Authentication, challengeHandler.AbandonChallenge)
 	challengeGroup.Get("/my", middleware.Authentication, challengeHandler.GetUserChallenges)
+	challengeGroup.Get("/proofs/:id", middleware.Authentication, challengeHandler.GetProofImage)
 	challengeGroup.Get("/badges", middleware.Authentication, challengeHandler.GetBadges)
 	challengeGroup.Get("/stats", middleware.Authentication, challengeHandler.GetUserStats)
+
+	adminChallengeGroup := adminGroup.Group("/challenges")
+	adminChallengeGroup.Get("/", challengeHandler.GetAllChallenges)
+	adminChallengeGroup.Post("/", challengeHandler.CreateChallenge)
+	adminChallengeGroup.Patch("/:id", challengeHandler.UpdateChallenge)
+	adminChallengeGroup.Post("/:id/activate", challengeHandler.ActivateChallenge)
+	adminChallengeGroup.Post("/:id/deactivate", challengeHandler.DeactivateChallenge)
+	adminChallengeGroup.Delete("/:id", challengeHandler.DeleteChallenge)
+
+	adminBadgeGroup := adminGroup.Group("/badges")
+	adminBadgeGroup.Get("/", challengeHandler.GetAllBadges)
+	adminBadgeGroup.Post("/", challengeHandler.CreateBadge)
+	adminBadgeGroup.Post("/backfill", challengeHandler.BackfillBadges)
+	adminBadgeGroup.Patch("/:id", challengeHandler.UpdateBadge)
+	adminBadgeGroup.Delete("/:id", challengeHandler.DeleteBadge)
+	adminBadgeGroup.Post("/:id/image", challengeHandler.UploadBadgeImage)
+
+	submissionGroup := moderationGroup.Group("/submissions")
+	submissionGroup.Get("/", challengeHandler.GetSubmissions)
+	submissionGroup.Post("/:id/approve", challengeHandler.ApproveSubmission)
+	submissionGroup.Post("/:id/reject", challengeHandler.RejectSubmission)
 }
 
 func (h *ChallengeHandler) GetChallenges(ctx *fiber.Ctx) error {
@@ -91,23 +115,72 @@ func (h *ChallengeHandler) CompleteChallenge(ctx *fiber.Ctx) error {
 		return res.ErrValidation(validationErrors)
 	}
 
-	newBadges, errRes := h.challengeUsecase.CompleteChallenge(userID, *req)
+	// The photo and track are optional at this layer; whether the challenge
+	// requires them is decided by the usecase.
+	photo, fileErr := ctx.FormFile("photo")
+	if fileErr != nil {
+		photo = nil
+	}
+
+	trackFile, fileErr := ctx.FormFile("track")
+	if fileErr != nil {
+		trackFile = nil
+	}
+
+	result, errRes := h.challengeUsecase.CompleteChallenge(userID, *req, photo, trackFile)
 	if errRes != nil {
 		return errRes
 	}
 
+	if result.Status == string(entity.StatusPendingReview) {
+		return res.OK(ctx, result, res.SubmitProofSuccess)
+	}
+
 	payload := map[string]interface{}{
 		"message":    res.CompleteChallengeSuccess,
-		"new_badges": newBadges,
+		"status":     result.Status,
+		"new_badges": result.NewBadges,
+		"level_up":   result.LevelUp,
 	}
 
-	if len(newBadges) > 0 {
+	if len(result.NewBadges) > 0 {
 		return res.OK(ctx, payload, res.BadgeUnlockedSuccess)
 	}
 
+	if result.LevelUp != nil {
+		return res.OK(ctx, payload, res.LevelUpSuccess)
+	}
+
 	return res.OK(ctx, payload, res.CompleteChallengeSuccess)
 }
 
+func (h *ChallengeHandler) AbandonChallenge(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.AbandonChallengeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	if errRes := h.challengeUsecase.AbandonChallenge(userID, *req); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.AbandonChallengeSuccess)
+}
+
 func (h *ChallengeHandler) GetUserChallenges(ctx *fiber.Ctx) error {
 	userID, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -122,6 +195,28 @@ func (h *ChallengeHandler) GetUserChallenges(ctx *fiber.Ctx) error {
 	return res.OK(ctx, challenges)
 }
 
+func (h *ChallengeHandler) GetProofImage(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, parseErr := uuid.Parse(ctx.Params("id"))
+	if parseErr != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	role, _ := ctx.Locals("role").(string)
+	file, contentType, errRes := h.challengeUsecase.GetProofImage(userID, role, id)
+	if errRes != nil {
+		return errRes
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+	return ctx.SendStream(file)
+}
+
 func (h *ChallengeHandler) GetBadges(ctx *fiber.Ctx) error {
 	userID, err := getUserIDFromContext(ctx)
 	if err != nil {
@@ -150,6 +245,296 @@ func (h *ChallengeHandler) GetUserStats(ctx *fiber.Ctx) error {
 	return res.OK(ctx, stats)
 }
 
+func (h *ChallengeHandler) GetAllChallenges(ctx *fiber.Ctx) error {
+	challenges, errRes := h.challengeUsecase.GetAllChallenges()
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, challenges)
+}
+
+func (h *ChallengeHandler) CreateChallenge(ctx *fiber.Ctx) error {
+	req := new(dto.CreateChallengeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	challenge, errRes := h.challengeUsecase.CreateChallenge(*req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.Created(ctx, challenge, res.CreateChallengeSuccess)
+}
+
+func (h *ChallengeHandler) UpdateChallenge(ctx *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	req := new(dto.UpdateChallengeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	challenge, errRes := h.challengeUsecase.UpdateChallenge(challengeID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, challenge, res.UpdateChallengeSuccess)
+}
+
+func (h *ChallengeHandler) ActivateChallenge(ctx *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if errRes := h.challengeUsecase.SetChallengeActive(challengeID, true); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.ActivateChallengeSuccess)
+}
+
+func (h *ChallengeHandler) DeactivateChallenge(ctx *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if errRes := h.challengeUsecase.SetChallengeActive(challengeID, false); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.DeactivateChallengeSuccess)
+}
+
+func (h *ChallengeHandler) DeleteChallenge(ctx *fiber.Ctx) error {
+	challengeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if errRes := h.challengeUsecase.DeleteChallenge(challengeID); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.DeleteChallengeSuccess)
+}
+
+func (h *ChallengeHandler) GetAllBadges(ctx *fiber.Ctx) error {
+	badges, errRes := h.challengeUsecase.GetAllBadges()
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, badges)
+}
+
+func (h *ChallengeHandler) CreateBadge(ctx *fiber.Ctx) error {
+	req := new(dto.CreateBadgeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	badge, errRes := h.challengeUsecase.CreateBadge(*req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.Created(ctx, badge, res.CreateBadgeSuccess)
+}
+
+func (h *ChallengeHandler) UpdateBadge(ctx *fiber.Ctx) error {
+	badgeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	req := new(dto.UpdateBadgeRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	badge, errRes := h.challengeUsecase.UpdateBadge(badgeID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, badge, res.UpdateBadgeSuccess)
+}
+
+func (h *ChallengeHandler) DeleteBadge(ctx *fiber.Ctx) error {
+	badgeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if errRes := h.challengeUsecase.DeleteBadge(badgeID); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.DeleteBadgeSuccess)
+}
+
+func (h *ChallengeHandler) UploadBadgeImage(ctx *fiber.Ctx) error {
+	badgeID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	file, err := ctx.FormFile("image")
+	if err != nil {
+		return res.ErrBadRequest(res.ImageRequired)
+	}
+
+	badge, errRes := h.challengeUsecase.UploadBadgeImage(badgeID, file)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, badge, res.UploadBadgeImageSuccess)
+}
+
+func (h *ChallengeHandler) BackfillBadges(ctx *fiber.Ctx) error {
+	awarded, errRes := h.challengeUsecase.BackfillBadges()
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, map[string]interface{}{"awarded": awarded}, res.BackfillBadgesSuccess)
+}
+
+func (h *ChallengeHandler) GetSubmissions(ctx *fiber.Ctx) error {
+	query := new(dto.GetSubmissionsQuery)
+	if err := ctx.QueryParser(query); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	submissions, errRes := h.challengeUsecase.GetSubmissions(*query)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, submissions)
+}
+
+func (h *ChallengeHandler) ApproveSubmission(ctx *fiber.Ctx) error {
+	moderatorID, errRes := getUserIDFromContext(ctx)
+	if errRes != nil {
+		return errRes
+	}
+
+	submissionID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	req := new(dto.ApproveSubmissionRequest)
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(req); err != nil {
+			return res.ErrBadRequest(res.FailedParsingRequestBody)
+		}
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	result, errRes := h.challengeUsecase.ApproveSubmission(moderatorID, submissionID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, result, res.ApproveSubmissionSuccess)
+}
+
+func (h *ChallengeHandler) RejectSubmission(ctx *fiber.Ctx) error {
+	moderatorID, errRes := getUserIDFromContext(ctx)
+	if errRes != nil {
+		return errRes
+	}
+
+	submissionID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	req := new(dto.RejectSubmissionRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	if errRes := h.challengeUsecase.RejectSubmission(moderatorID, submissionID, *req); errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, nil, res.RejectSubmissionSuccess)
+}
+
 func getUserIDFromContext(ctx *fiber.Ctx) (uuid.UUID, *res.Err) {
 	userIDStr := ctx.Locals("user_id")
 	if userIDStr == nil {
@@ -2,21 +2,49 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/Ablebil/eco-sample/internal/domain/entity"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ChallengeRepositoryItf interface {
+	Transaction(fn func(repo ChallengeRepositoryItf) error) error
 	GetActiveChallenges() ([]entity.Challenge, error)
+	GetChallenges() ([]entity.Challenge, error)
+	CreateChallenge(challenge *entity.Challenge) error
+	UpdateChallenge(challenge *entity.Challenge) error
+	SetChallengeActive(id uuid.UUID, active bool) error
+	DeleteChallenge(id uuid.UUID) error
 	GetChallengeByID(id uuid.UUID) (*entity.Challenge, error)
 	GetUserChallenges(userID uuid.UUID) ([]entity.UserChallenge, error)
-	TakeChallenge(userID, challengeID uuid.UUID) error
-	CompleteChallenge(userID, challengeID uuid.UUID) error
-	GetUserChallenge(userID, challengeID uuid.UUID) (*entity.UserChallenge, error)
+	TakeChallenge(userChallenge *entity.UserChallenge) error
+	RetakeChallenge(id uuid.UUID, dueAt time.Time) (bool, error)
+	FailOverdueChallenges(now time.Time) (int64, error)
+	AbandonChallenge(id uuid.UUID) (bool, error)
+	CountOngoingChallenges(userID uuid.UUID) (int64, error)
+	LockUser(userID uuid.UUID) error
+	CompleteChallenge(id uuid.UUID) (bool, error)
+	SubmitChallengeProof(id uuid.UUID, imageURL, note *string) (bool, error)
+	GetPendingSubmissions(challengeID *uuid.UUID, limit, offset int) ([]entity.UserChallenge, int64, error)
+	GetUserChallengeByID(id uuid.UUID) (*entity.UserChallenge, error)
+	ReviewSubmission(id uuid.UUID, status entity.ChallengeStatus, reason *string) (bool, error)
+	CreateModerationDecision(decision *entity.ModerationDecision) error
+	GetUserChallenge(userID, challengeID uuid.UUID, periodKey string) (*entity.UserChallenge, error)
+	GetLatestUserChallenge(userID, challengeID uuid.UUID) (*entity.UserChallenge, error)
 	UpdateUserExp(userID uuid.UUID, expToAdd int) error
+	RecordImpact(record *entity.ImpactRecord) error
+	SaveTrackSummary(summary *entity.TrackSummary) error
+	DeleteTrackSummary(userChallengeID uuid.UUID) error
 	GetBadges() ([]entity.Badge, error)
+	GetBadgeByID(id uuid.UUID) (*entity.Badge, error)
+	GetBadgeByType(badgeType entity.BadgeType) (*entity.Badge, error)
+	CreateBadge(badge *entity.Badge) error
+	UpdateBadge(badge *entity.Badge) error
+	DeleteBadge(id uuid.UUID) error
+	BackfillBadges() (int64, error)
 	GetUserBadges(userID uuid.UUID) ([]entity.UserBadge, error)
 	UnlockBadge(userID, badgeID uuid.UUID) error
 	GetUserByID(userID uuid.UUID) (*entity.User, error)
@@ -30,12 +58,48 @@ func NewChallengeRepository(db *gorm.DB) ChallengeRepositoryItf {
 	return &ChallengeRepository{db}
 }
 
+// Transaction runs fn against a repository bound to a single database
+// transaction, committing only if fn returns nil.
+func (r *ChallengeRepository) Transaction(fn func(repo ChallengeRepositoryItf) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&ChallengeRepository{tx})
+	})
+}
+
 func (r *ChallengeRepository) GetActiveChallenges() ([]entity.Challenge, error) {
 	var challenges []entity.Challenge
 	err := r.db.Where("is_active = ?", true).Find(&challenges).Error
 	return challenges, err
 }
 
+func (r *ChallengeRepository) GetChallenges() ([]entity.Challenge, error) {
+	var challenges []entity.Challenge
+	err := r.db.Order("created_at DESC").Find(&challenges).Error
+	return challenges, err
+}
+
+func (r *ChallengeRepository) CreateChallenge(challenge *entity.Challenge) error {
+	// Select every column so an explicit is_active = false is not replaced
+	// by the column default.
+	return r.db.Select("*").Create(challenge).Error
+}
+
+func (r *ChallengeRepository) UpdateChallenge(challenge *entity.Challenge) error {
+	return r.db.Model(challenge).
+		Select("title", "description", "exp_reward", "co2e_kg", "water_liters", "plastic_items", "duration_days", "recurrence", "proof_type", "track_mode").
+		Updates(challenge).Error
+}
+
+func (r *ChallengeRepository) SetChallengeActive(id uuid.UUID, active bool) error {
+	return r.db.Model(&entity.Challenge{}).
+		Where("id = ?", id).
+		Update("is_active", active).Error
+}
+
+func (r *ChallengeRepository) DeleteChallenge(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&entity.Challenge{}).Error
+}
+
 func (r *ChallengeRepository) GetChallengeByID(id uuid.UUID) (*entity.Challenge, error) {
 	var challenge entity.Challenge
 	err := r.db.Where("id = ?", id).First(&challenge).Error
@@ -53,31 +117,199 @@ func (r *ChallengeRepository) GetChallengeByID(id uuid.UUID) (*entity.Challenge,
 
 func (r *ChallengeRepository) GetUserChallenges(userID uuid.UUID) ([]entity.UserChallenge, error) {
 	var userChallenges []entity.UserChallenge
-	err := r.db.Preload("Challenge").Where("user_id = ?", userID).Find(&userChallenges).Error
+	err := r.db.Preload("Challenge", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Preload("Track").Where("user_id = ?", userID).Find(&userChallenges).Error
 	return userChallenges, err
 }
 
-func (r *ChallengeRepository) TakeChallenge(userID, challengeID uuid.UUID) error {
-	userChallenge := entity.UserChallenge{
-		UserID:      userID,
-		ChallengeID: challengeID,
-		Status:      entity.StatusOngoing,
-	}
-	return r.db.Create(&userChallenge).Error
+func (r *ChallengeRepository) TakeChallenge(userChallenge *entity.UserChallenge) error {
+	return r.db.Create(userChallenge).Error
+}
+
+func (r *ChallengeRepository) RetakeChallenge(id uuid.UUID, dueAt time.Time) (bool, error) {
+	result := r.db.Model(&entity.UserChallenge{}).
+		Where("id = ? AND status IN ?", id, []entity.ChallengeStatus{entity.StatusFailed, entity.StatusAbandoned, entity.StatusRejected}).
+		Updates(map[string]interface{}{
+			"status":          entity.StatusOngoing,
+			"started_at":      time.Now(),
+			"due_at":          dueAt,
+			"failed_at":       nil,
+			"abandoned_at":    nil,
+			"completed_at":    nil,
+			"proof_image_url": nil,
+			"proof_note":      nil,
+			"submitted_at":    nil,
+			"reviewed_at":     nil,
+			"review_reason":   nil,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChallengeRepository) AbandonChallenge(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&entity.UserChallenge{}).
+		Where("id = ? AND status = ?", id, entity.StatusOngoing).
+		Updates(map[string]interface{}{
+			"status":       entity.StatusAbandoned,
+			"abandoned_at": time.Now(),
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChallengeRepository) CountOngoingChallenges(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&entity.UserChallenge{}).
+		Where("user_id = ? AND status = ?", userID, entity.StatusOngoing).
+		Count(&count).Error
+	return count, err
+}
+
+// LockUser takes a row lock on the user for the rest of the transaction,
+// serializing per-user checks such as the ongoing challenge limit.
+func (r *ChallengeRepository) LockUser(userID uuid.UUID) error {
+	var user entity.User
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", userID).
+		First(&user).Error
+}
+
+func (r *ChallengeRepository) FailOverdueChallenges(now time.Time) (int64, error) {
+	result := r.db.Model(&entity.UserChallenge{}).
+		Where("status = ? AND due_at <= ?", entity.StatusOngoing, now).
+		Updates(map[string]interface{}{
+			"status":    entity.StatusFailed,
+			"failed_at": now,
+		})
+
+	return result.RowsAffected, result.Error
 }
 
-func (r *ChallengeRepository) CompleteChallenge(userID, challengeID uuid.UUID) error {
-	return r.db.Model(&entity.UserChallenge{}).
-		Where("user_id = ? AND challenge_id = ?", userID, challengeID).
+// CompleteChallenge only moves an ongoing challenge to completed and reports
+// whether it did, so concurrent completions cannot both succeed.
+func (r *ChallengeRepository) CompleteChallenge(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&entity.UserChallenge{}).
+		Where("id = ? AND status = ?", id, entity.StatusOngoing).
+		Where("due_at IS NULL OR due_at > ?", time.Now()).
 		Updates(map[string]interface{}{
 			"status":       entity.StatusCompleted,
 			"completed_at": gorm.Expr("NOW()"),
-		}).Error
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// SubmitChallengeProof moves an ongoing challenge to pending review with its
+// proof attached, under the same conditions as CompleteChallenge.
+func (r *ChallengeRepository) SubmitChallengeProof(id uuid.UUID, imageURL, note *string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&entity.UserChallenge{}).
+		Where("id = ? AND status = ?", id, entity.StatusOngoing).
+		Where("due_at IS NULL OR due_at > ?", now).
+		Updates(map[string]interface{}{
+			"status":          entity.StatusPendingReview,
+			"proof_image_url": imageURL,
+			"proof_note":      note,
+			"submitted_at":    now,
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChallengeRepository) GetPendingSubmissions(challengeID *uuid.UUID, limit, offset int) ([]entity.UserChallenge, int64, error) {
+	query := r.db.Model(&entity.UserChallenge{}).Where("status = ?", entity.StatusPendingReview)
+	if challengeID != nil {
+		query = query.Where("challenge_id = ?", *challengeID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var userChallenges []entity.UserChallenge
+	err := query.
+		Preload("User").
+		Preload("Track").
+		Preload("Challenge", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Order("submitted_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&userChallenges).Error
+
+	return userChallenges, total, err
+}
+
+func (r *ChallengeRepository) GetUserChallengeByID(id uuid.UUID) (*entity.UserChallenge, error) {
+	var userChallenge entity.UserChallenge
+	err := r.db.Preload("Challenge", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("id = ?", id).First(&userChallenge).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &userChallenge, nil
+}
+
+// ReviewSubmission settles a pending submission as completed or rejected and
+// reports whether it was still pending, so a submission is decided only once.
+func (r *ChallengeRepository) ReviewSubmission(id uuid.UUID, status entity.ChallengeStatus, reason *string) (bool, error) {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":        status,
+		"reviewed_at":   now,
+		"review_reason": reason,
+	}
+
+	if status == entity.StatusCompleted {
+		updates["completed_at"] = now
+	}
+
+	result := r.db.Model(&entity.UserChallenge{}).
+		Where("id = ? AND status = ?", id, entity.StatusPendingReview).
+		Updates(updates)
+
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *ChallengeRepository) CreateModerationDecision(decision *entity.ModerationDecision) error {
+	return r.db.Create(decision).Error
 }
 
-func (r *ChallengeRepository) GetUserChallenge(userID, challengeID uuid.UUID) (*entity.UserChallenge, error) {
+func (r *ChallengeRepository) GetUserChallenge(userID, challengeID uuid.UUID, periodKey string) (*entity.UserChallenge, error) {
 	var userChallenge entity.UserChallenge
-	err := r.db.Where("user_id = ? AND challenge_id = ?", userID, challengeID).First(&userChallenge).Error
+	err := r.db.Where("user_id = ? AND challenge_id = ? AND period_key = ?", userID, challengeID, periodKey).First(&userChallenge).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &userChallenge, nil
+}
+
+// GetLatestUserChallenge loads the challenge even when it has been deleted
+// since, so attempts already taken can still be finished.
+func (r *ChallengeRepository) GetLatestUserChallenge(userID, challengeID uuid.UUID) (*entity.UserChallenge, error) {
+	var userChallenge entity.UserChallenge
+	err := r.db.Preload("Challenge", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("user_id = ? AND challenge_id = ?", userID, challengeID).
+		Order("created_at DESC").
+		First(&userChallenge).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -96,12 +328,106 @@ func (r *ChallengeRepository) UpdateUserExp(userID uuid.UUID, expToAdd int) erro
 		Update("exp", gorm.Expr("exp + ?", expToAdd)).Error
 }
 
+// RecordImpact stores the impact of a completed attempt and adds it to the
+// user's running totals. An attempt is only ever credited once.
+func (r *ChallengeRepository) RecordImpact(record *entity.ImpactRecord) error {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_challenge_id"}},
+		DoNothing: true,
+	}).Create(record)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return result.Error
+	}
+
+	return r.db.Model(&entity.User{}).
+		Where("id = ?", record.UserID).
+		Updates(map[string]interface{}{
+			"total_co2e_kg":       gorm.Expr("total_co2e_kg + ?", record.Co2eKg),
+			"total_water_liters":  gorm.Expr("total_water_liters + ?", record.WaterLiters),
+			"total_plastic_items": gorm.Expr("total_plastic_items + ?", record.PlasticItems),
+		}).Error
+}
+
+// SaveTrackSummary attaches a track to an attempt, replacing any track a
+// previous submission of the same attempt left behind.
+func (r *ChallengeRepository) SaveTrackSummary(summary *entity.TrackSummary) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_challenge_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"file_hash", "format", "points", "distance_meters", "duration_seconds",
+			"avg_speed_kmh", "max_speed_kmh", "started_at", "ended_at",
+		}),
+	}).Create(summary).Error
+}
+
+func (r *ChallengeRepository) DeleteTrackSummary(userChallengeID uuid.UUID) error {
+	return r.db.Where("user_challenge_id = ?", userChallengeID).Delete(&entity.TrackSummary{}).Error
+}
+
 func (r *ChallengeRepository) GetBadges() ([]entity.Badge, error) {
 	var badges []entity.Badge
 	err := r.db.Order("required_exp ASC").Find(&badges).Error
 	return badges, err
 }
 
+func (r *ChallengeRepository) GetBadgeByID(id uuid.UUID) (*entity.Badge, error) {
+	var badge entity.Badge
+	err := r.db.Where("id = ?", id).First(&badge).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &badge, nil
+}
+
+func (r *ChallengeRepository) GetBadgeByType(badgeType entity.BadgeType) (*entity.Badge, error) {
+	var badge entity.Badge
+	err := r.db.Where("type = ?", badgeType).First(&badge).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &badge, nil
+}
+
+func (r *ChallengeRepository) CreateBadge(badge *entity.Badge) error {
+	return r.db.Create(badge).Error
+}
+
+func (r *ChallengeRepository) UpdateBadge(badge *entity.Badge) error {
+	return r.db.Model(badge).
+		Select("name", "description", "image_url", "required_exp").
+		Updates(badge).Error
+}
+
+func (r *ChallengeRepository) DeleteBadge(id uuid.UUID) error {
+	return r.db.Where("id = ?", id).Delete(&entity.Badge{}).Error
+}
+
+// BackfillBadges awards every badge to every user whose exp already meets
+// its threshold and returns how many badges were newly unlocked.
+func (r *ChallengeRepository) BackfillBadges() (int64, error) {
+	result := r.db.Exec(`
+		INSERT INTO user_badges (user_id, badge_id, unlocked_at)
+		SELECT u.id, b.id, NOW()
+		FROM users u
+		JOIN badges b ON u.exp >= b.required_exp
+		ON CONFLICT (user_id, badge_id) DO NOTHING
+	`)
+
+	return result.RowsAffected, result.Error
+}
+
 func (r *ChallengeRepository) GetUserBadges(userID uuid.UUID) ([]entity.UserBadge, error) {
 	var userBadges []entity.UserBadge
 	err := r.db.Preload("Badge").Where("user_id = ?", userID).Find(&userBadges).Error
@@ -113,7 +439,7 @@ func (r *ChallengeRepository) UnlockBadge(userID, badgeID uuid.UUID) error {
 		UserID:  userID,
 		BadgeID: badgeID,
 	}
-	return r.db.Create(&userBadge).Error
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&userBadge).Error
 }
 
 func (r *ChallengeRepository) GetUserByID(userID uuid.UUID) (*entity.User, error) {
@@ -1,50 +1,145 @@
 package usecase
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/Ablebil/eco-sample/config"
+	activityUsecase "github.com/Ablebil/eco-sample/internal/app/activity/usecase"
 	challengeRepository "github.com/Ablebil/eco-sample/internal/app/challenge/repository"
 	"github.com/Ablebil/eco-sample/internal/domain/dto"
 	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	"github.com/Ablebil/eco-sample/internal/infra/level"
+	"github.com/Ablebil/eco-sample/internal/infra/media"
+	"github.com/Ablebil/eco-sample/internal/infra/postgresql"
 	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/infra/storage"
+	"github.com/Ablebil/eco-sample/internal/infra/track"
 	"github.com/google/uuid"
 )
 
 type ChallengeUsecaseItf interface {
 	GetChallenges(userID uuid.UUID) ([]dto.GetChallengesResponse, *res.Err)
 	TakeChallenge(userID uuid.UUID, req dto.TakeChallengeRequest) *res.Err
-	CompleteChallenge(userID uuid.UUID, req dto.CompleteChallengeRequest) ([]dto.GetBadgesResponse, *res.Err)
+	CompleteChallenge(userID uuid.UUID, req dto.CompleteChallengeRequest, photo, trackFile *multipart.FileHeader) (*dto.CompleteChallengeResponse, *res.Err)
+	AbandonChallenge(userID uuid.UUID, req dto.AbandonChallengeRequest) *res.Err
 	GetUserChallenges(userID uuid.UUID) ([]dto.GetUserChallengesResponse, *res.Err)
+	GetProofImage(userID uuid.UUID, role string, id uuid.UUID) (io.ReadCloser, string, *res.Err)
 	GetBadges(userID uuid.UUID) ([]dto.GetBadgesResponse, *res.Err)
 	GetUserStats(userID uuid.UUID) (*dto.GetUserStatsResponse, *res.Err)
+	GetAllChallenges() ([]dto.GetChallengesResponse, *res.Err)
+	CreateChallenge(req dto.CreateChallengeRequest) (*dto.GetChallengesResponse, *res.Err)
+	UpdateChallenge(id uuid.UUID, req dto.UpdateChallengeRequest) (*dto.GetChallengesResponse, *res.Err)
+	SetChallengeActive(id uuid.UUID, active bool) *res.Err
+	DeleteChallenge(id uuid.UUID) *res.Err
+	GetAllBadges() ([]dto.BadgeResponse, *res.Err)
+	CreateBadge(req dto.CreateBadgeRequest) (*dto.BadgeResponse, *res.Err)
+	UpdateBadge(id uuid.UUID, req dto.UpdateBadgeRequest) (*dto.BadgeResponse, *res.Err)
+	DeleteBadge(id uuid.UUID) *res.Err
+	UploadBadgeImage(id uuid.UUID, file *multipart.FileHeader) (*dto.BadgeResponse, *res.Err)
+	BackfillBadges() (int64, *res.Err)
+	BackfillPendingBadges() (int64, *res.Err)
+	FailOverdueChallenges() (int64, *res.Err)
+	GetSubmissions(query dto.GetSubmissionsQuery) (*dto.GetSubmissionsResponse, *res.Err)
+	ApproveSubmission(moderatorID, id uuid.UUID, req dto.ApproveSubmissionRequest) (*dto.CompleteChallengeResponse, *res.Err)
+	RejectSubmission(moderatorID, id uuid.UUID, req dto.RejectSubmissionRequest) *res.Err
 }
 
 type ChallengeUsecase struct {
 	challengeRepository challengeRepository.ChallengeRepositoryItf
+	activityUsecase     activityUsecase.ActivityUsecaseItf
+	storage             storage.StorageItf
+	proofStorage        storage.StorageItf
+	levelCurve          level.CurveItf
+	cfg                 *config.Config
+	location            *time.Location
+
+	// badgeBackfillPending is set when a badge change may award it to users
+	// who already have enough exp; the scheduler picks it up.
+	badgeBackfillPending atomic.Bool
 }
 
-func NewChallengeUsecase(challengeRepository challengeRepository.ChallengeRepositoryItf) ChallengeUsecaseItf {
+func NewChallengeUsecase(challengeRepository challengeRepository.ChallengeRepositoryItf, activityUsecase activityUsecase.ActivityUsecaseItf, storage, proofStorage storage.StorageItf, levelCurve level.CurveItf, cfg *config.Config) ChallengeUsecaseItf {
 	return &ChallengeUsecase{
 		challengeRepository: challengeRepository,
+		activityUsecase:     activityUsecase,
+		storage:             storage,
+		proofStorage:        proofStorage,
+		levelCurve:          levelCurve,
+		cfg:                 cfg,
+		location:            cfg.Location,
 	}
 }
 
+var (
+	errChallengeNotOngoing = errors.New("challenge is not ongoing")
+	errSubmissionReviewed  = errors.New("submission already reviewed")
+)
+
+const (
+	defaultSubmissionsLimit = 20
+
+	// trackClockSkew tolerates devices whose clock runs slightly ahead.
+	trackClockSkew = 5 * time.Minute
+)
+
+type trackSpeedLimit struct {
+	maxAvgKmh   float64
+	maxSpeedKmh float64
+}
+
+// trackSpeedLimits bound what a human-powered trip can plausibly average and
+// sustain for a minute; anything faster is treated as motorized. Public
+// transport is motorized by nature, so its limit only rules out flights.
+var trackSpeedLimits = map[entity.ChallengeTrackMode]trackSpeedLimit{
+	entity.TrackWalking:         {maxAvgKmh: 12, maxSpeedKmh: 25},
+	entity.TrackCycling:         {maxAvgKmh: 35, maxSpeedKmh: 60},
+	entity.TrackPublicTransport: {maxAvgKmh: 120, maxSpeedKmh: 200},
+}
+
+var imageExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+}
+
 func (uc *ChallengeUsecase) GetChallenges(userID uuid.UUID) ([]dto.GetChallengesResponse, *res.Err) {
 	challenges, err := uc.challengeRepository.GetActiveChallenges()
 	if err != nil {
 		return nil, res.ErrInternalServerError(res.FailedGetChallenges)
 	}
 
+	now := time.Now().In(uc.location)
+
 	var response []dto.GetChallengesResponse
 	for _, challenge := range challenges {
+		periodKey, _ := challengePeriod(challenge.Recurrence, now)
+
 		challengeResponse := dto.GetChallengesResponse{
-			ID:          challenge.ID,
-			Title:       challenge.Title,
-			Description: challenge.Description,
-			ExpReward:   challenge.ExpReward,
-			IsActive:    challenge.IsActive,
-			CreatedAt:   *challenge.CreatedAt,
+			ID:           challenge.ID,
+			Title:        challenge.Title,
+			Description:  challenge.Description,
+			ExpReward:    challenge.ExpReward,
+			DurationDays: challenge.DurationDays,
+			Recurrence:   string(challenge.Recurrence),
+			ProofType:    string(challenge.ProofType),
+			PeriodKey:    periodKey,
+			IsActive:     challenge.IsActive,
+			CreatedAt:    *challenge.CreatedAt,
 		}
 
-		userChallenge, err := uc.challengeRepository.GetUserChallenge(userID, challenge.ID)
+		userChallenge, err := uc.challengeRepository.GetUserChallenge(userID, challenge.ID, periodKey)
 		if err != nil {
 			return nil, res.ErrInternalServerError(res.FailedGetUserChallenges)
 		}
@@ -52,6 +147,7 @@ func (uc *ChallengeUsecase) GetChallenges(userID uuid.UUID) ([]dto.GetChallenges
 		if userChallenge != nil {
 			status := string(userChallenge.Status)
 			challengeResponse.Status = &status
+			challengeResponse.CompletedThisPeriod = userChallenge.Status == entity.StatusCompleted
 		}
 
 		response = append(response, challengeResponse)
@@ -74,24 +170,119 @@ func (uc *ChallengeUsecase) TakeChallenge(userID uuid.UUID, req dto.TakeChalleng
 		return res.ErrBadRequest(res.ChallengeNotActive)
 	}
 
-	userChallenge, err := uc.challengeRepository.GetUserChallenge(userID, req.ChallengeID)
+	now := time.Now().In(uc.location)
+	periodKey, periodEnd := challengePeriod(challenge.Recurrence, now)
+
+	userChallenge, err := uc.challengeRepository.GetUserChallenge(userID, req.ChallengeID, periodKey)
 	if err != nil {
 		return res.ErrInternalServerError(res.FailedGetUserChallenges)
 	}
 
 	if userChallenge != nil {
-		return res.ErrConflict(res.ChallengeAlreadyTaken)
+		if userChallenge.Status == entity.StatusCompleted && challenge.Recurrence != entity.RecurrenceNone {
+			return res.ErrConflict(res.ChallengeCompletedThisPeriod)
+		}
+
+		if userChallenge.Status != entity.StatusFailed && userChallenge.Status != entity.StatusAbandoned && userChallenge.Status != entity.StatusRejected {
+			return res.ErrConflict(res.ChallengeAlreadyTaken)
+		}
+
+		if retakeAt := uc.retakeAvailableAt(userChallenge); retakeAt != nil && time.Now().Before(*retakeAt) {
+			errRes := res.ErrTooManyRequests(res.ChallengeRetakeCooldown)
+			errRes.Payload = map[string]interface{}{
+				"retry_after": int(time.Until(*retakeAt).Seconds()),
+			}
+			return errRes
+		}
+	}
+
+	// A recurring attempt belongs to its period, so it cannot outlive it.
+	dueAt := now.AddDate(0, 0, challenge.DurationDays)
+	if periodEnd != nil && periodEnd.Before(dueAt) {
+		dueAt = *periodEnd
+	}
+
+	var errRes *res.Err
+	err = uc.challengeRepository.Transaction(func(repo challengeRepository.ChallengeRepositoryItf) error {
+		if err := repo.LockUser(userID); err != nil {
+			return err
+		}
+
+		if uc.cfg.MaxOngoingChallenges > 0 {
+			ongoing, err := repo.CountOngoingChallenges(userID)
+			if err != nil {
+				return err
+			}
+
+			if ongoing >= int64(uc.cfg.MaxOngoingChallenges) {
+				errRes = res.ErrConflict(res.OngoingChallengeLimit)
+				return errRes
+			}
+		}
+
+		if userChallenge == nil {
+			return repo.TakeChallenge(&entity.UserChallenge{
+				UserID:      userID,
+				ChallengeID: req.ChallengeID,
+				PeriodKey:   periodKey,
+				Status:      entity.StatusOngoing,
+				StartedAt:   &now,
+				DueAt:       &dueAt,
+			})
+		}
+
+		retaken, err := repo.RetakeChallenge(userChallenge.ID, dueAt)
+		if err != nil {
+			return err
+		}
+
+		if !retaken {
+			errRes = res.ErrConflict(res.ChallengeAlreadyTaken)
+			return errRes
+		}
+
+		return repo.DeleteTrackSummary(userChallenge.ID)
+	})
+
+	if errRes != nil {
+		return errRes
 	}
 
-	if err := uc.challengeRepository.TakeChallenge(userID, req.ChallengeID); err != nil {
+	if err != nil {
+		if postgresql.CheckError(err, postgresql.ErrUniqueViolation) {
+			return res.ErrConflict(res.ChallengeAlreadyTaken)
+		}
+
 		return res.ErrInternalServerError(res.FailedTakeChallenge)
 	}
 
 	return nil
 }
 
-func (uc *ChallengeUsecase) CompleteChallenge(userID uuid.UUID, req dto.CompleteChallengeRequest) ([]dto.GetBadgesResponse, *res.Err) {
-	userChallenge, err := uc.challengeRepository.GetUserChallenge(userID, req.ChallengeID)
+func (uc *ChallengeUsecase) AbandonChallenge(userID uuid.UUID, req dto.AbandonChallengeRequest) *res.Err {
+	userChallenge, err := uc.challengeRepository.GetLatestUserChallenge(userID, req.ChallengeID)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGetUserChallenges)
+	}
+
+	if userChallenge == nil {
+		return res.ErrNotFound(res.ChallengeNotTaken)
+	}
+
+	abandoned, err := uc.challengeRepository.AbandonChallenge(userChallenge.ID)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedAbandonChallenge)
+	}
+
+	if !abandoned {
+		return res.ErrConflict(res.ChallengeNotOngoing)
+	}
+
+	return nil
+}
+
+func (uc *ChallengeUsecase) CompleteChallenge(userID uuid.UUID, req dto.CompleteChallengeRequest, photo, trackFile *multipart.FileHeader) (*dto.CompleteChallengeResponse, *res.Err) {
+	userChallenge, err := uc.challengeRepository.GetLatestUserChallenge(userID, req.ChallengeID)
 	if err != nil {
 		return nil, res.ErrInternalServerError(res.FailedGetUserChallenges)
 	}
@@ -104,33 +295,304 @@ func (uc *ChallengeUsecase) CompleteChallenge(userID uuid.UUID, req dto.Complete
 		return nil, res.ErrConflict(res.ChallengeAlreadyCompleted)
 	}
 
+	if userChallenge.Status == entity.StatusPendingReview {
+		return nil, res.ErrConflict(res.ChallengeUnderReview)
+	}
+
 	if userChallenge.Status != entity.StatusOngoing {
 		return nil, res.ErrBadRequest(res.ChallengeNotTaken)
 	}
 
-	challenge, err := uc.challengeRepository.GetChallengeByID(req.ChallengeID)
-	if err != nil {
-		return nil, res.ErrInternalServerError(res.FailedGetChallenges)
+	if userChallenge.DueAt != nil && !time.Now().Before(*userChallenge.DueAt) {
+		return nil, res.ErrBadRequest(res.ChallengeOverdue)
 	}
 
+	challenge := userChallenge.Challenge
 	if challenge == nil {
 		return nil, res.ErrNotFound(res.ChallengeNotFound)
 	}
 
-	if err := uc.challengeRepository.CompleteChallenge(userID, req.ChallengeID); err != nil {
-		return nil, res.ErrInternalServerError(res.FailedCompleteChallenge)
+	var trackSummary *entity.TrackSummary
+	if challenge.TrackMode != "" && challenge.TrackMode != entity.TrackNone {
+		if trackFile == nil {
+			return nil, res.ErrBadRequest(res.TrackRequired)
+		}
+
+		var errRes *res.Err
+		trackSummary, errRes = uc.verifyTrack(userChallenge, challenge.TrackMode, trackFile)
+		if errRes != nil {
+			return nil, errRes
+		}
 	}
 
-	if err := uc.challengeRepository.UpdateUserExp(userID, challenge.ExpReward); err != nil {
-		return nil, res.ErrInternalServerError(res.FailedUpdateUserExp)
+	if challenge.ProofType != "" && challenge.ProofType != entity.ProofNone {
+		return uc.submitProof(userChallenge, challenge, req, photo, trackSummary)
 	}
 
-	newBadges, errRes := uc.checkAndUnlockBadges(userID)
-	if errRes != nil {
+	var reward *dto.CompleteChallengeResponse
+	var errRes *res.Err
+
+	err = uc.challengeRepository.Transaction(func(repo challengeRepository.ChallengeRepositoryItf) error {
+		completed, err := repo.CompleteChallenge(userChallenge.ID)
+		if err != nil {
+			errRes = res.ErrInternalServerError(res.FailedCompleteChallenge)
+			return err
+		}
+
+		if !completed {
+			return errChallengeNotOngoing
+		}
+
+		if trackSummary != nil {
+			if errRes = saveTrackSummary(repo, trackSummary); errRes != nil {
+				return errRes
+			}
+		}
+
+		reward, errRes = uc.grantReward(repo, userChallenge, challenge)
+		if errRes != nil {
+			return errRes
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errChallengeNotOngoing) {
+		return nil, res.ErrConflict(res.ChallengeAlreadyCompleted)
+	}
+
+	if err != nil {
+		if errRes == nil {
+			errRes = res.ErrInternalServerError(res.FailedCompleteChallenge)
+		}
+
 		return nil, errRes
 	}
 
-	return newBadges, nil
+	return reward, nil
+}
+
+func (uc *ChallengeUsecase) submitProof(userChallenge *entity.UserChallenge, challenge *entity.Challenge, req dto.CompleteChallengeRequest, photo *multipart.FileHeader, trackSummary *entity.TrackSummary) (*dto.CompleteChallengeResponse, *res.Err) {
+	needsPhoto := challenge.ProofType == entity.ProofPhoto || challenge.ProofType == entity.ProofPhotoAndNote
+	needsNote := challenge.ProofType == entity.ProofNote || challenge.ProofType == entity.ProofPhotoAndNote
+
+	if needsPhoto && photo == nil {
+		return nil, res.ErrBadRequest(res.ProofPhotoRequired)
+	}
+
+	var note *string
+	if trimmed := strings.TrimSpace(req.Note); trimmed != "" {
+		note = &trimmed
+	}
+
+	if needsNote && note == nil {
+		return nil, res.ErrBadRequest(res.ProofNoteRequired)
+	}
+
+	var imageURL *string
+	if photo != nil {
+		url, errRes := uc.uploadProofPhoto(userChallenge.ID, photo)
+		if errRes != nil {
+			return nil, errRes
+		}
+
+		imageURL = &url
+	}
+
+	var errRes *res.Err
+	err := uc.challengeRepository.Transaction(func(repo challengeRepository.ChallengeRepositoryItf) error {
+		submitted, err := repo.SubmitChallengeProof(userChallenge.ID, imageURL, note)
+		if err != nil {
+			return err
+		}
+
+		if !submitted {
+			errRes = res.ErrConflict(res.ChallengeNotOngoing)
+			return errRes
+		}
+
+		if trackSummary != nil {
+			if errRes = saveTrackSummary(repo, trackSummary); errRes != nil {
+				return errRes
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if imageURL != nil {
+			uc.proofStorage.Delete(*imageURL)
+		}
+
+		if errRes == nil {
+			errRes = res.ErrInternalServerError(res.FailedSubmitProof)
+		}
+
+		return nil, errRes
+	}
+
+	return &dto.CompleteChallengeResponse{
+		Status:    string(entity.StatusPendingReview),
+		NewBadges: []dto.GetBadgesResponse{},
+	}, nil
+}
+
+// verifyTrack parses an uploaded GPX or FIT file and checks that it is a
+// plausible recording of the challenge's mode of travel.
+func (uc *ChallengeUsecase) verifyTrack(userChallenge *entity.UserChallenge, mode entity.ChallengeTrackMode, trackFile *multipart.FileHeader) (*entity.TrackSummary, *res.Err) {
+	if trackFile.Size > uc.cfg.MaxTrackSize {
+		return nil, res.ErrBadRequest(res.TrackTooLarge)
+	}
+
+	src, err := trackFile.Open()
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedReadTrack)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, uc.cfg.MaxTrackSize+1))
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedReadTrack)
+	}
+
+	if int64(len(data)) > uc.cfg.MaxTrackSize {
+		return nil, res.ErrBadRequest(res.TrackTooLarge)
+	}
+
+	summary, err := track.Parse(data)
+	if errors.Is(err, track.ErrUnsupportedFormat) {
+		return nil, res.ErrBadRequest(res.UnsupportedTrackFormat)
+	}
+
+	if err != nil {
+		return nil, res.ErrBadRequest(res.InvalidTrack)
+	}
+
+	if summary.EndedAt.After(time.Now().Add(trackClockSkew)) {
+		return nil, res.ErrBadRequest(res.InvalidTrack)
+	}
+
+	if startedAt := attemptStartedAt(userChallenge); startedAt != nil && summary.StartedAt.Before(startedAt.Add(-trackClockSkew)) {
+		return nil, res.ErrBadRequest(res.TrackOutsideAttempt)
+	}
+
+	if userChallenge.DueAt != nil && summary.EndedAt.After(userChallenge.DueAt.Add(trackClockSkew)) {
+		return nil, res.ErrBadRequest(res.TrackOutsideAttempt)
+	}
+
+	if summary.DistanceMeters < uc.cfg.TrackMinDistanceMeters {
+		return nil, res.ErrBadRequest(res.TrackTooShort)
+	}
+
+	limit := trackSpeedLimits[mode]
+	if summary.AvgSpeedKmh > limit.maxAvgKmh || summary.MaxSpeedKmh > limit.maxSpeedKmh {
+		return nil, res.ErrBadRequest(res.TrackLooksMotorized)
+	}
+
+	hash := sha256.Sum256(data)
+	return &entity.TrackSummary{
+		UserChallengeID: userChallenge.ID,
+		UserID:          userChallenge.UserID,
+		FileHash:        hex.EncodeToString(hash[:]),
+		Format:          string(summary.Format),
+		Points:          summary.Points,
+		DistanceMeters:  math.Round(summary.DistanceMeters*100) / 100,
+		DurationSeconds: int(summary.Duration.Seconds()),
+		AvgSpeedKmh:     math.Round(summary.AvgSpeedKmh*100) / 100,
+		MaxSpeedKmh:     math.Round(summary.MaxSpeedKmh*100) / 100,
+		StartedAt:       summary.StartedAt,
+		EndedAt:         summary.EndedAt,
+	}, nil
+}
+
+func (uc *ChallengeUsecase) uploadProofPhoto(userChallengeID uuid.UUID, photo *multipart.FileHeader) (string, *res.Err) {
+	if photo.Size > uc.cfg.MaxImageSize {
+		return "", res.ErrBadRequest(res.ImageTooLarge)
+	}
+
+	src, err := photo.Open()
+	if err != nil {
+		return "", res.ErrInternalServerError(res.FailedUploadImage)
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(io.LimitReader(src, uc.cfg.MaxImageSize+1))
+	if err != nil {
+		return "", res.ErrInternalServerError(res.FailedUploadImage)
+	}
+
+	if int64(len(data)) > uc.cfg.MaxImageSize {
+		return "", res.ErrBadRequest(res.ImageTooLarge)
+	}
+
+	// Photos are often taken on phones that embed GPS coordinates.
+	data, err = media.StripMetadata(data)
+	if err != nil {
+		return "", res.ErrBadRequest(res.UnsupportedProofImageType)
+	}
+
+	contentType, _ := media.DetectImageType(data)
+
+	name, err := uuid.NewV7()
+	if err != nil {
+		return "", res.ErrInternalServerError(res.FailedUploadImage)
+	}
+
+	key := "proofs/" + userChallengeID.String() + "-" + name.String() + imageExtensions[contentType]
+	url, err := uc.proofStorage.Upload(key, bytes.NewReader(data))
+	if err != nil {
+		return "", res.ErrInternalServerError(res.FailedUploadImage)
+	}
+
+	return url, nil
+}
+
+// GetProofImage streams a proof photo to the user who submitted it or to a
+// moderator. The caller must close the returned reader.
+func (uc *ChallengeUsecase) GetProofImage(userID uuid.UUID, role string, id uuid.UUID) (io.ReadCloser, string, *res.Err) {
+	userChallenge, err := uc.challengeRepository.GetUserChallengeByID(id)
+	if err != nil {
+		return nil, "", res.ErrInternalServerError(res.FailedGetProofImage)
+	}
+
+	if userChallenge == nil || userChallenge.ProofImageURL == nil {
+		return nil, "", res.ErrNotFound(res.ProofImageNotFound)
+	}
+
+	isModerator := role == string(entity.RoleModerator) || role == string(entity.RoleAdmin)
+	if userChallenge.UserID != userID && !isModerator {
+		return nil, "", res.ErrNotFound(res.ProofImageNotFound)
+	}
+
+	file, err := uc.proofStorage.Open(*userChallenge.ProofImageURL)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, "", res.ErrNotFound(res.ProofImageNotFound)
+	}
+
+	if err != nil {
+		return nil, "", res.ErrInternalServerError(res.FailedGetProofImage)
+	}
+
+	contentType := "application/octet-stream"
+	for imageType, extension := range imageExtensions {
+		if strings.HasSuffix(*userChallenge.ProofImageURL, extension) {
+			contentType = imageType
+		}
+	}
+
+	return file, contentType, nil
+}
+
+// proofImageURL points clients at the authenticated proof route instead of the
+// storage key, which is not publicly reachable.
+func (uc *ChallengeUsecase) proofImageURL(userChallenge *entity.UserChallenge) *string {
+	if userChallenge.ProofImageURL == nil {
+		return nil
+	}
+
+	url := strings.TrimRight(uc.cfg.AppURL, "/") + "/api/v1/challenges/proofs/" + userChallenge.ID.String()
+	return &url
 }
 
 func (uc *ChallengeUsecase) GetUserChallenges(userID uuid.UUID) ([]dto.GetUserChallengesResponse, *res.Err) {
@@ -142,13 +604,36 @@ func (uc *ChallengeUsecase) GetUserChallenges(userID uuid.UUID) ([]dto.GetUserCh
 	var response []dto.GetUserChallengesResponse
 	for _, userChallenge := range userChallenges {
 		challengeResponse := dto.GetUserChallengesResponse{
+			ID:          userChallenge.ID,
 			ChallengeID: userChallenge.ChallengeID,
+			PeriodKey:   userChallenge.PeriodKey,
 			Title:       userChallenge.Challenge.Title,
 			Description: userChallenge.Challenge.Description,
 			ExpReward:   userChallenge.Challenge.ExpReward,
 			Status:      string(userChallenge.Status),
+			DueAt:       userChallenge.DueAt,
 			CompletedAt: userChallenge.CompletedAt,
-			CreatedAt:   *userChallenge.CreatedAt,
+			FailedAt:    userChallenge.FailedAt,
+			AbandonedAt: userChallenge.AbandonedAt,
+			SubmittedAt: userChallenge.SubmittedAt,
+
+			ProofImageURL: uc.proofImageURL(&userChallenge),
+			ProofNote:     userChallenge.ProofNote,
+			ReviewedAt:    userChallenge.ReviewedAt,
+			ReviewReason:  userChallenge.ReviewReason,
+			CreatedAt:     *userChallenge.CreatedAt,
+
+			Track: toTrackSummaryResponse(userChallenge.Track),
+		}
+
+		switch userChallenge.Status {
+		case entity.StatusOngoing:
+			if userChallenge.DueAt != nil {
+				remaining := max(int64(time.Until(*userChallenge.DueAt).Seconds()), 0)
+				challengeResponse.RemainingSeconds = &remaining
+			}
+		case entity.StatusFailed, entity.StatusAbandoned:
+			challengeResponse.RetakeAvailableAt = uc.retakeAvailableAt(&userChallenge)
 		}
 
 		response = append(response, challengeResponse)
@@ -213,12 +698,21 @@ func (uc *ChallengeUsecase) GetUserStats(userID uuid.UUID) (*dto.GetUserStatsRes
 
 	completedCount := 0
 	ongoingCount := 0
+	failedCount := 0
+	abandonedCount := 0
+	pendingCount := 0
 	for _, userChallenge := range userChallenges {
 		switch userChallenge.Status {
 		case entity.StatusCompleted:
 			completedCount++
 		case entity.StatusOngoing:
 			ongoingCount++
+		case entity.StatusFailed:
+			failedCount++
+		case entity.StatusAbandoned:
+			abandonedCount++
+		case entity.StatusPendingReview:
+			pendingCount++
 		}
 	}
 
@@ -227,33 +721,728 @@ func (uc *ChallengeUsecase) GetUserStats(userID uuid.UUID) (*dto.GetUserStatsRes
 		return nil, errRes
 	}
 
+	activityTrend, errRes := uc.activityUsecase.GetActivityTrend(userID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	progress := uc.levelCurve.Progress(user.Exp)
 	response := &dto.GetUserStatsResponse{
 		CurrentExp:      user.Exp,
+		Level:           progress.Level,
+		ExpIntoLevel:    progress.ExpIntoLevel,
+		ExpToNextLevel:  progress.ExpToNextLevel,
 		TotalChallenges: len(userChallenges),
 		CompletedCount:  completedCount,
 		OngoingCount:    ongoingCount,
-		Badges:          badges,
+		FailedCount:     failedCount,
+		AbandonedCount:  abandonedCount,
+		PendingCount:    pendingCount,
+		Impact: dto.ImpactTotals{
+			Co2eKg:       user.TotalCo2eKg,
+			WaterLiters:  user.TotalWaterLiters,
+			PlasticItems: user.TotalPlasticItems,
+		},
+		ActivityTrend: *activityTrend,
+		Badges:        badges,
 	}
 
 	return response, nil
 }
 
-func (uc *ChallengeUsecase) checkAndUnlockBadges(userID uuid.UUID) ([]dto.GetBadgesResponse, *res.Err) {
-	user, err := uc.challengeRepository.GetUserByID(userID)
+func (uc *ChallengeUsecase) GetAllChallenges() ([]dto.GetChallengesResponse, *res.Err) {
+	challenges, err := uc.challengeRepository.GetChallenges()
 	if err != nil {
-		return nil, res.ErrInternalServerError(res.FailedFindUser)
+		return nil, res.ErrInternalServerError(res.FailedGetChallenges)
 	}
 
-	if user == nil {
-		return nil, res.ErrNotFound(res.UserNotFound)
+	response := make([]dto.GetChallengesResponse, 0, len(challenges))
+	for _, challenge := range challenges {
+		response = append(response, toChallengeResponse(&challenge))
 	}
 
-	badges, err := uc.challengeRepository.GetBadges()
+	return response, nil
+}
+
+func (uc *ChallengeUsecase) CreateChallenge(req dto.CreateChallengeRequest) (*dto.GetChallengesResponse, *res.Err) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	durationDays := req.DurationDays
+	if durationDays == 0 {
+		durationDays = 1
+	}
+
+	recurrence := entity.ChallengeRecurrence(req.Recurrence)
+	if recurrence == "" {
+		recurrence = entity.RecurrenceNone
+	}
+
+	proofType := entity.ChallengeProofType(req.ProofType)
+	if proofType == "" {
+		proofType = entity.ProofNone
+	}
+
+	trackMode := entity.ChallengeTrackMode(req.TrackMode)
+	if trackMode == "" {
+		trackMode = entity.TrackNone
+	}
+
+	challenge := &entity.Challenge{
+		Title:        req.Title,
+		Description:  req.Description,
+		ExpReward:    req.ExpReward,
+		Co2eKg:       req.Co2eKg,
+		WaterLiters:  req.WaterLiters,
+		PlasticItems: req.PlasticItems,
+		DurationDays: durationDays,
+		Recurrence:   recurrence,
+		ProofType:    proofType,
+		TrackMode:    trackMode,
+		IsActive:     isActive,
+	}
+
+	if err := uc.challengeRepository.CreateChallenge(challenge); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedCreateChallenge)
+	}
+
+	response := toChallengeResponse(challenge)
+	return &response, nil
+}
+
+func (uc *ChallengeUsecase) UpdateChallenge(id uuid.UUID, req dto.UpdateChallengeRequest) (*dto.GetChallengesResponse, *res.Err) {
+	challenge, err := uc.challengeRepository.GetChallengeByID(id)
 	if err != nil {
-		return nil, res.ErrInternalServerError(res.FailedGetBadges)
+		return nil, res.ErrInternalServerError(res.FailedGetChallenges)
 	}
 
-	userBadges, err := uc.challengeRepository.GetUserBadges(userID)
+	if challenge == nil {
+		return nil, res.ErrNotFound(res.ChallengeNotFound)
+	}
+
+	if req.Title != nil {
+		challenge.Title = *req.Title
+	}
+
+	if req.Description != nil {
+		challenge.Description = req.Description
+	}
+
+	if req.ExpReward != nil {
+		challenge.ExpReward = *req.ExpReward
+	}
+
+	if req.Co2eKg != nil {
+		challenge.Co2eKg = *req.Co2eKg
+	}
+
+	if req.WaterLiters != nil {
+		challenge.WaterLiters = *req.WaterLiters
+	}
+
+	if req.PlasticItems != nil {
+		challenge.PlasticItems = *req.PlasticItems
+	}
+
+	if req.DurationDays != nil {
+		challenge.DurationDays = *req.DurationDays
+	}
+
+	if req.Recurrence != nil {
+		challenge.Recurrence = entity.ChallengeRecurrence(*req.Recurrence)
+	}
+
+	if req.ProofType != nil {
+		challenge.ProofType = entity.ChallengeProofType(*req.ProofType)
+	}
+
+	if req.TrackMode != nil {
+		challenge.TrackMode = entity.ChallengeTrackMode(*req.TrackMode)
+	}
+
+	if err := uc.challengeRepository.UpdateChallenge(challenge); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUpdateChallenge)
+	}
+
+	response := toChallengeResponse(challenge)
+	return &response, nil
+}
+
+func (uc *ChallengeUsecase) SetChallengeActive(id uuid.UUID, active bool) *res.Err {
+	challenge, err := uc.challengeRepository.GetChallengeByID(id)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGetChallenges)
+	}
+
+	if challenge == nil {
+		return res.ErrNotFound(res.ChallengeNotFound)
+	}
+
+	// Users who already took the challenge keep their progress; inactive
+	// challenges only stop accepting new participants.
+	if err := uc.challengeRepository.SetChallengeActive(challenge.ID, active); err != nil {
+		return res.ErrInternalServerError(res.FailedUpdateChallenge)
+	}
+
+	return nil
+}
+
+func (uc *ChallengeUsecase) DeleteChallenge(id uuid.UUID) *res.Err {
+	challenge, err := uc.challengeRepository.GetChallengeByID(id)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGetChallenges)
+	}
+
+	if challenge == nil {
+		return res.ErrNotFound(res.ChallengeNotFound)
+	}
+
+	if err := uc.challengeRepository.DeleteChallenge(challenge.ID); err != nil {
+		return res.ErrInternalServerError(res.FailedDeleteChallenge)
+	}
+
+	return nil
+}
+
+func (uc *ChallengeUsecase) GetAllBadges() ([]dto.BadgeResponse, *res.Err) {
+	badges, err := uc.challengeRepository.GetBadges()
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetBadges)
+	}
+
+	response := make([]dto.BadgeResponse, 0, len(badges))
+	for _, badge := range badges {
+		response = append(response, toBadgeResponse(&badge))
+	}
+
+	return response, nil
+}
+
+func (uc *ChallengeUsecase) CreateBadge(req dto.CreateBadgeRequest) (*dto.BadgeResponse, *res.Err) {
+	existing, err := uc.challengeRepository.GetBadgeByType(entity.BadgeType(req.Type))
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetBadges)
+	}
+
+	if existing != nil {
+		return nil, res.ErrConflict(res.BadgeTypeAlreadyExists)
+	}
+
+	badge := &entity.Badge{
+		Type:        entity.BadgeType(req.Type),
+		Name:        req.Name,
+		Description: req.Description,
+		RequiredExp: req.RequiredExp,
+	}
+
+	if err := uc.challengeRepository.CreateBadge(badge); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedCreateBadge)
+	}
+
+	uc.badgeBackfillPending.Store(true)
+
+	response := toBadgeResponse(badge)
+	return &response, nil
+}
+
+func (uc *ChallengeUsecase) UpdateBadge(id uuid.UUID, req dto.UpdateBadgeRequest) (*dto.BadgeResponse, *res.Err) {
+	badge, err := uc.challengeRepository.GetBadgeByID(id)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetBadges)
+	}
+
+	if badge == nil {
+		return nil, res.ErrNotFound(res.BadgeNotFound)
+	}
+
+	lowered := req.RequiredExp != nil && *req.RequiredExp < badge.RequiredExp
+
+	if req.Name != nil {
+		badge.Name = *req.Name
+	}
+
+	if req.Description != nil {
+		badge.Description = req.Description
+	}
+
+	if req.RequiredExp != nil {
+		badge.RequiredExp = *req.RequiredExp
+	}
+
+	if err := uc.challengeRepository.UpdateBadge(badge); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUpdateBadge)
+	}
+
+	// Raising a threshold never takes a badge away, but lowering one must
+	// award it to users who already have enough exp.
+	if lowered {
+		uc.badgeBackfillPending.Store(true)
+	}
+
+	response := toBadgeResponse(badge)
+	return &response, nil
+}
+
+func (uc *ChallengeUsecase) DeleteBadge(id uuid.UUID) *res.Err {
+	badge, err := uc.challengeRepository.GetBadgeByID(id)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedGetBadges)
+	}
+
+	if badge == nil {
+		return res.ErrNotFound(res.BadgeNotFound)
+	}
+
+	if err := uc.challengeRepository.DeleteBadge(badge.ID); err != nil {
+		return res.ErrInternalServerError(res.FailedDeleteBadge)
+	}
+
+	if badge.ImageURL != nil {
+		if err := uc.storage.Delete(*badge.ImageURL); err != nil {
+			log.Printf("Failed to delete image of badge %s: %v", badge.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (uc *ChallengeUsecase) UploadBadgeImage(id uuid.UUID, file *multipart.FileHeader) (*dto.BadgeResponse, *res.Err) {
+	if file.Size > uc.cfg.MaxImageSize {
+		return nil, res.ErrBadRequest(res.ImageTooLarge)
+	}
+
+	badge, err := uc.challengeRepository.GetBadgeByID(id)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetBadges)
+	}
+
+	if badge == nil {
+		return nil, res.ErrNotFound(res.BadgeNotFound)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUploadImage)
+	}
+	defer src.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, res.ErrBadRequest(res.UnsupportedImageType)
+	}
+
+	ext, ok := imageExtensions[http.DetectContentType(head[:n])]
+	if !ok {
+		return nil, res.ErrBadRequest(res.UnsupportedImageType)
+	}
+
+	name, err := uuid.NewV7()
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUploadImage)
+	}
+
+	key := "badges/" + badge.ID.String() + "-" + name.String() + ext
+	url, err := uc.storage.Upload(key, io.MultiReader(bytes.NewReader(head[:n]), src))
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUploadImage)
+	}
+
+	oldURL := badge.ImageURL
+	badge.ImageURL = &url
+
+	if err := uc.challengeRepository.UpdateBadge(badge); err != nil {
+		if err := uc.storage.Delete(url); err != nil {
+			log.Printf("Failed to delete unused image of badge %s: %v", badge.ID, err)
+		}
+
+		return nil, res.ErrInternalServerError(res.FailedUpdateBadge)
+	}
+
+	if oldURL != nil {
+		if err := uc.storage.Delete(*oldURL); err != nil {
+			log.Printf("Failed to delete previous image of badge %s: %v", badge.ID, err)
+		}
+	}
+
+	response := toBadgeResponse(badge)
+	return &response, nil
+}
+
+func (uc *ChallengeUsecase) BackfillBadges() (int64, *res.Err) {
+	awarded, err := uc.challengeRepository.BackfillBadges()
+	if err != nil {
+		return 0, res.ErrInternalServerError(res.FailedBackfillBadges)
+	}
+
+	return awarded, nil
+}
+
+// BackfillPendingBadges runs BackfillBadges only when a badge was created or
+// had its threshold lowered since the last run.
+func (uc *ChallengeUsecase) BackfillPendingBadges() (int64, *res.Err) {
+	if !uc.badgeBackfillPending.Swap(false) {
+		return 0, nil
+	}
+
+	awarded, errRes := uc.BackfillBadges()
+	if errRes != nil {
+		uc.badgeBackfillPending.Store(true)
+	}
+
+	return awarded, errRes
+}
+
+func (uc *ChallengeUsecase) FailOverdueChallenges() (int64, *res.Err) {
+	failed, err := uc.challengeRepository.FailOverdueChallenges(time.Now())
+	if err != nil {
+		return 0, res.ErrInternalServerError(res.FailedFailOverdueChallenges)
+	}
+
+	return failed, nil
+}
+
+func (uc *ChallengeUsecase) GetSubmissions(query dto.GetSubmissionsQuery) (*dto.GetSubmissionsResponse, *res.Err) {
+	page := max(query.Page, 1)
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultSubmissionsLimit
+	}
+
+	var challengeID *uuid.UUID
+	if query.ChallengeID != "" {
+		id, err := uuid.Parse(query.ChallengeID)
+		if err != nil {
+			return nil, res.ErrBadRequest(res.FailedParsingRequestParams)
+		}
+		challengeID = &id
+	}
+
+	userChallenges, total, err := uc.challengeRepository.GetPendingSubmissions(challengeID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetSubmissions)
+	}
+
+	submissions := make([]dto.SubmissionResponse, 0, len(userChallenges))
+	for _, userChallenge := range userChallenges {
+		submission := dto.SubmissionResponse{
+			ID:            userChallenge.ID,
+			UserID:        userChallenge.UserID,
+			ChallengeID:   userChallenge.ChallengeID,
+			PeriodKey:     userChallenge.PeriodKey,
+			ProofImageURL: uc.proofImageURL(&userChallenge),
+			ProofNote:     userChallenge.ProofNote,
+			SubmittedAt:   userChallenge.SubmittedAt,
+
+			Track: toTrackSummaryResponse(userChallenge.Track),
+		}
+
+		if userChallenge.User != nil {
+			submission.UserName = userChallenge.User.Name
+		}
+
+		if userChallenge.Challenge != nil {
+			submission.ChallengeTitle = userChallenge.Challenge.Title
+			submission.ExpReward = userChallenge.Challenge.ExpReward
+		}
+
+		submissions = append(submissions, submission)
+	}
+
+	return &dto.GetSubmissionsResponse{
+		Submissions: submissions,
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+	}, nil
+}
+
+func (uc *ChallengeUsecase) ApproveSubmission(moderatorID, id uuid.UUID, req dto.ApproveSubmissionRequest) (*dto.CompleteChallengeResponse, *res.Err) {
+	userChallenge, errRes := uc.getReviewableSubmission(moderatorID, id)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	var reason *string
+	if req.Reason != "" {
+		reason = &req.Reason
+	}
+
+	var reward *dto.CompleteChallengeResponse
+	err := uc.challengeRepository.Transaction(func(repo challengeRepository.ChallengeRepositoryItf) error {
+		reviewed, err := repo.ReviewSubmission(userChallenge.ID, entity.StatusCompleted, reason)
+		if err != nil {
+			errRes = res.ErrInternalServerError(res.FailedReviewSubmission)
+			return err
+		}
+
+		if !reviewed {
+			return errSubmissionReviewed
+		}
+
+		reward, errRes = uc.grantReward(repo, userChallenge, userChallenge.Challenge)
+		if errRes != nil {
+			return errRes
+		}
+
+		return repo.CreateModerationDecision(&entity.ModerationDecision{
+			UserChallengeID: userChallenge.ID,
+			ModeratorID:     moderatorID,
+			Decision:        entity.DecisionApproved,
+			Reason:          reason,
+		})
+	})
+
+	if errRes := reviewError(err, errRes); errRes != nil {
+		return nil, errRes
+	}
+
+	return reward, nil
+}
+
+func (uc *ChallengeUsecase) RejectSubmission(moderatorID, id uuid.UUID, req dto.RejectSubmissionRequest) *res.Err {
+	userChallenge, errRes := uc.getReviewableSubmission(moderatorID, id)
+	if errRes != nil {
+		return errRes
+	}
+
+	err := uc.challengeRepository.Transaction(func(repo challengeRepository.ChallengeRepositoryItf) error {
+		reviewed, err := repo.ReviewSubmission(userChallenge.ID, entity.StatusRejected, &req.Reason)
+		if err != nil {
+			return err
+		}
+
+		if !reviewed {
+			return errSubmissionReviewed
+		}
+
+		return repo.CreateModerationDecision(&entity.ModerationDecision{
+			UserChallengeID: userChallenge.ID,
+			ModeratorID:     moderatorID,
+			Decision:        entity.DecisionRejected,
+			Reason:          &req.Reason,
+		})
+	})
+
+	return reviewError(err, nil)
+}
+
+func (uc *ChallengeUsecase) getReviewableSubmission(moderatorID, id uuid.UUID) (*entity.UserChallenge, *res.Err) {
+	userChallenge, err := uc.challengeRepository.GetUserChallengeByID(id)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetSubmissions)
+	}
+
+	if userChallenge == nil || userChallenge.Challenge == nil {
+		return nil, res.ErrNotFound(res.SubmissionNotFound)
+	}
+
+	if userChallenge.UserID == moderatorID {
+		return nil, res.ErrForbidden(res.CannotReviewOwnSubmission)
+	}
+
+	if userChallenge.Status != entity.StatusPendingReview {
+		return nil, res.ErrConflict(res.SubmissionAlreadyReviewed)
+	}
+
+	return userChallenge, nil
+}
+
+func reviewError(err error, errRes *res.Err) *res.Err {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, errSubmissionReviewed) {
+		return res.ErrConflict(res.SubmissionAlreadyReviewed)
+	}
+
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.ErrInternalServerError(res.FailedReviewSubmission)
+}
+
+// grantReward credits a completed attempt with its exp, impact, any badges
+// newly reached and a level-up if there is one. Completions that skip review
+// and approved submissions both go through it.
+func (uc *ChallengeUsecase) grantReward(repo challengeRepository.ChallengeRepositoryItf, userChallenge *entity.UserChallenge, challenge *entity.Challenge) (*dto.CompleteChallengeResponse, *res.Err) {
+	if err := repo.UpdateUserExp(userChallenge.UserID, challenge.ExpReward); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedUpdateUserExp)
+	}
+
+	if err := recordImpact(repo, userChallenge, challenge); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedRecordImpact)
+	}
+
+	newBadges, errRes := checkAndUnlockBadges(repo, userChallenge.UserID)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	levelUp, errRes := uc.detectLevelUp(repo, userChallenge.UserID, challenge.ExpReward)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	return &dto.CompleteChallengeResponse{
+		Status:    string(entity.StatusCompleted),
+		NewBadges: newBadges,
+		LevelUp:   levelUp,
+	}, nil
+}
+
+// detectLevelUp compares the user's level before and after gaining exp. It
+// must run after the EXP update so it sees the new total.
+func (uc *ChallengeUsecase) detectLevelUp(repo challengeRepository.ChallengeRepositoryItf, userID uuid.UUID, gained int) (*dto.LevelUpResponse, *res.Err) {
+	user, err := repo.GetUserByID(userID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, res.ErrNotFound(res.UserNotFound)
+	}
+
+	previousLevel := uc.levelCurve.Level(user.Exp - gained)
+	progress := uc.levelCurve.Progress(user.Exp)
+	if progress.Level <= previousLevel {
+		return nil, nil
+	}
+
+	return &dto.LevelUpResponse{
+		PreviousLevel:  previousLevel,
+		Level:          progress.Level,
+		ExpToNextLevel: progress.ExpToNextLevel,
+	}, nil
+}
+
+func (uc *ChallengeUsecase) retakeAvailableAt(userChallenge *entity.UserChallenge) *time.Time {
+	endedAt := userChallenge.FailedAt
+	if endedAt == nil {
+		endedAt = userChallenge.AbandonedAt
+	}
+
+	if endedAt == nil {
+		return nil
+	}
+
+	retakeAt := endedAt.Add(uc.cfg.ChallengeRetakeCooldown)
+	return &retakeAt
+}
+
+// Attempts taken before started_at existed fall back to the row's creation
+// time, which is still correct for everything but retakes.
+func attemptStartedAt(userChallenge *entity.UserChallenge) *time.Time {
+	if userChallenge.StartedAt != nil {
+		return userChallenge.StartedAt
+	}
+
+	return userChallenge.CreatedAt
+}
+
+func toTrackSummaryResponse(summary *entity.TrackSummary) *dto.TrackSummaryResponse {
+	if summary == nil {
+		return nil
+	}
+
+	return &dto.TrackSummaryResponse{
+		Format:          summary.Format,
+		Points:          summary.Points,
+		DistanceMeters:  summary.DistanceMeters,
+		DurationSeconds: summary.DurationSeconds,
+		AvgSpeedKmh:     summary.AvgSpeedKmh,
+		MaxSpeedKmh:     summary.MaxSpeedKmh,
+		StartedAt:       summary.StartedAt,
+		EndedAt:         summary.EndedAt,
+	}
+}
+
+func toBadgeResponse(badge *entity.Badge) dto.BadgeResponse {
+	response := dto.BadgeResponse{
+		ID:          badge.ID,
+		Type:        string(badge.Type),
+		Name:        badge.Name,
+		Description: badge.Description,
+		ImageURL:    badge.ImageURL,
+		RequiredExp: badge.RequiredExp,
+	}
+
+	if badge.CreatedAt != nil {
+		response.CreatedAt = *badge.CreatedAt
+	}
+
+	return response
+}
+
+func toChallengeResponse(challenge *entity.Challenge) dto.GetChallengesResponse {
+	response := dto.GetChallengesResponse{
+		ID:           challenge.ID,
+		Title:        challenge.Title,
+		Description:  challenge.Description,
+		ExpReward:    challenge.ExpReward,
+		Co2eKg:       challenge.Co2eKg,
+		WaterLiters:  challenge.WaterLiters,
+		PlasticItems: challenge.PlasticItems,
+		DurationDays: challenge.DurationDays,
+		Recurrence:   string(challenge.Recurrence),
+		ProofType:    string(challenge.ProofType),
+		TrackMode:    string(challenge.TrackMode),
+		IsActive:     challenge.IsActive,
+	}
+
+	if challenge.CreatedAt != nil {
+		response.CreatedAt = *challenge.CreatedAt
+	}
+
+	return response
+}
+
+// recordImpact credits the challenge's estimated impact for a completed
+// attempt, using the estimate as it stands at completion time.
+func recordImpact(repo challengeRepository.ChallengeRepositoryItf, userChallenge *entity.UserChallenge, challenge *entity.Challenge) error {
+	return repo.RecordImpact(&entity.ImpactRecord{
+		UserID:          userChallenge.UserID,
+		UserChallengeID: userChallenge.ID,
+		ChallengeID:     challenge.ID,
+		Co2eKg:          challenge.Co2eKg,
+		WaterLiters:     challenge.WaterLiters,
+		PlasticItems:    challenge.PlasticItems,
+		RecordedAt:      time.Now(),
+	})
+}
+
+func saveTrackSummary(repo challengeRepository.ChallengeRepositoryItf, summary *entity.TrackSummary) *res.Err {
+	if err := repo.SaveTrackSummary(summary); err != nil {
+		if postgresql.CheckError(err, postgresql.ErrUniqueViolation) {
+			return res.ErrConflict(res.TrackAlreadyUsed)
+		}
+
+		return res.ErrInternalServerError(res.FailedSaveTrack)
+	}
+
+	return nil
+}
+
+func checkAndUnlockBadges(repo challengeRepository.ChallengeRepositoryItf, userID uuid.UUID) ([]dto.GetBadgesResponse, *res.Err) {
+	user, err := repo.GetUserByID(userID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, res.ErrNotFound(res.UserNotFound)
+	}
+
+	badges, err := repo.GetBadges()
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetBadges)
+	}
+
+	userBadges, err := repo.GetUserBadges(userID)
 	if err != nil {
 		return nil, res.ErrInternalServerError(res.FailedGetUserBadges)
 	}
@@ -267,7 +1456,7 @@ func (uc *ChallengeUsecase) checkAndUnlockBadges(userID uuid.UUID) ([]dto.GetBad
 
 	for _, badge := range badges {
 		if user.Exp >= badge.RequiredExp && !unlockedBadgeIds[badge.ID] {
-			if err := uc.challengeRepository.UnlockBadge(userID, badge.ID); err != nil {
+			if err := repo.UnlockBadge(userID, badge.ID); err != nil {
 				return nil, res.ErrInternalServerError(res.FailedUnlockBadge)
 			}
 
@@ -0,0 +1,195 @@
+//go:build integration
+
+package usecase
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ablebil/eco-sample/config"
+	challengeRepository "github.com/Ablebil/eco-sample/internal/app/challenge/repository"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	"github.com/Ablebil/eco-sample/internal/infra/level"
+	"github.com/Ablebil/eco-sample/internal/infra/postgresql"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"gorm.io/gorm"
+)
+
+const parallelCompletions = 10
+
+// openTestDB connects to the Postgres database named by TEST_DATABASE_DSN. The
+// completion guard relies on row locks, so these tests need a real database and
+// only build with the integration tag (make test-integration).
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Fatal("TEST_DATABASE_DSN must be set to run the integration tests")
+	}
+
+	db, err := postgresql.New(dsn, &config.Config{AppEnv: "production"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := postgresql.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+type completionFixture struct {
+	user          *entity.User
+	challenge     *entity.Challenge
+	badge         *entity.Badge
+	userChallenge *entity.UserChallenge
+}
+
+func newCompletionFixture(t *testing.T, db *gorm.DB) *completionFixture {
+	t.Helper()
+
+	user := &entity.User{Email: "complete-" + time.Now().Format("150405.000000") + "@example.com", Name: "Racer"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	challenge := &entity.Challenge{Title: "Parallel completion", ExpReward: 50, IsActive: true}
+	if err := db.Create(challenge).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	badge := &entity.Badge{Type: entity.BadgeEcoWarrior, Name: "Parallel completion", RequiredExp: challenge.ExpReward}
+	if err := db.Create(badge).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	userChallenge := &entity.UserChallenge{UserID: user.ID, ChallengeID: challenge.ID, Status: entity.StatusOngoing}
+	if err := db.Create(userChallenge).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Delete(&entity.User{}, "id = ?", user.ID)
+		db.Unscoped().Delete(&entity.Challenge{}, "id = ?", challenge.ID)
+		db.Delete(&entity.Badge{}, "id = ?", badge.ID)
+	})
+
+	return &completionFixture{
+		user:          user,
+		challenge:     challenge,
+		badge:         badge,
+		userChallenge: userChallenge,
+	}
+}
+
+func TestRepositoryCompleteChallengeOnce(t *testing.T) {
+	db := openTestDB(t)
+	fixture := newCompletionFixture(t, db)
+	repo := challengeRepository.NewChallengeRepository(db)
+
+	var wg sync.WaitGroup
+	results := make(chan bool, parallelCompletions)
+	for range parallelCompletions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			completed, err := repo.CompleteChallenge(fixture.userChallenge.ID)
+			if err != nil {
+				t.Error(err)
+			}
+
+			results <- completed
+		}()
+	}
+
+	wg.Wait()
+	close(results)
+
+	completions := 0
+	for completed := range results {
+		if completed {
+			completions++
+		}
+	}
+
+	if completions != 1 {
+		t.Errorf("%d updates affected the ongoing row, want 1", completions)
+	}
+}
+
+func TestCompleteChallengeGrantsRewardsOnce(t *testing.T) {
+	db := openTestDB(t)
+	fixture := newCompletionFixture(t, db)
+
+	cfg := &config.Config{
+		LevelCurve:   level.CurveFormula,
+		LevelBaseExp: 100,
+		LevelGrowth:  1.2,
+		LevelMax:     50,
+		Location:     time.UTC,
+	}
+
+	levelCurve, err := level.NewCurve(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uc := NewChallengeUsecase(challengeRepository.NewChallengeRepository(db), nil, nil, nil, levelCurve, cfg)
+	req := dto.CompleteChallengeRequest{ChallengeID: fixture.challenge.ID}
+
+	var wg sync.WaitGroup
+	errs := make(chan *res.Err, parallelCompletions)
+	for range parallelCompletions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, errRes := uc.CompleteChallenge(fixture.user.ID, req, nil, nil)
+			errs <- errRes
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	successes := 0
+	for errRes := range errs {
+		if errRes == nil {
+			successes++
+			continue
+		}
+
+		if errRes.Code != http.StatusConflict {
+			t.Errorf("unexpected error %d: %s", errRes.Code, errRes.Message)
+		}
+	}
+
+	if successes != 1 {
+		t.Fatalf("%d completions succeeded, want 1", successes)
+	}
+
+	var user entity.User
+	if err := db.First(&user, "id = ?", fixture.user.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Exp != fixture.challenge.ExpReward {
+		t.Errorf("exp = %d, want %d", user.Exp, fixture.challenge.ExpReward)
+	}
+
+	var userBadges int64
+	if err := db.Model(&entity.UserBadge{}).Where("user_id = ? AND badge_id = ?", fixture.user.ID, fixture.badge.ID).Count(&userBadges).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if userBadges != 1 {
+		t.Errorf("%d user badges, want 1", userBadges)
+	}
+}
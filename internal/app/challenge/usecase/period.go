@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+)
+
+const nonRecurringPeriod = "once"
+
+// challengePeriod returns the key of the recurrence period containing t and
+// the moment that period ends. Non-recurring challenges have a single period
+// that never ends.
+func challengePeriod(recurrence entity.ChallengeRecurrence, t time.Time) (string, *time.Time) {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+
+	var key string
+	var end time.Time
+
+	switch recurrence {
+	case entity.RecurrenceDaily:
+		key = day.Format("2006-01-02")
+		end = day.AddDate(0, 0, 1)
+	case entity.RecurrenceWeekly:
+		year, week := t.ISOWeek()
+		key = fmt.Sprintf("%d-W%02d", year, week)
+		monday := day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+		end = monday.AddDate(0, 0, 7)
+	case entity.RecurrenceMonthly:
+		key = day.Format("2006-01")
+		end = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return nonRecurringPeriod, nil
+	}
+
+	return key, &end
+}
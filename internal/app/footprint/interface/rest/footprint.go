@@ -0,0 +1,107 @@
+package rest
+
+import (
+	"github.com/Ablebil/eco-sample/internal/app/footprint/usecase"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type FootprintHandler struct {
+	validator        *validator.Validate
+	footprintUsecase usecase.FootprintUsecaseItf
+}
+
+func NewFootprintHandler(footprintGroup fiber.Router, validator *validator.Validate, footprintUsecase usecase.FootprintUsecaseItf, middleware middleware.MiddlewareItf) {
+	footprintHandler := FootprintHandler{
+		validator:        validator,
+		footprintUsecase: footprintUsecase,
+	}
+
+	footprintGroup = footprintGroup.Group("/footprint")
+	footprintGroup.Get("/factors", middleware.Authentication, footprintHandler.GetEmissionFactors)
+	footprintGroup.Post("/assessments", middleware.Authentication, footprintHandler.CreateAssessment)
+	footprintGroup.Get("/assessments", middleware.Authentication, footprintHandler.GetAssessments)
+}
+
+func (h *FootprintHandler) GetEmissionFactors(ctx *fiber.Ctx) error {
+	factors, errRes := h.footprintUsecase.GetEmissionFactors(ctx.Query("region"))
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, factors)
+}
+
+func (h *FootprintHandler) CreateAssessment(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.FootprintAssessmentRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	assessment, errRes := h.footprintUsecase.CreateAssessment(userID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.Created(ctx, assessment, res.CreateAssessmentSuccess)
+}
+
+func (h *FootprintHandler) GetAssessments(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := new(dto.GetAssessmentsQuery)
+	if err := ctx.QueryParser(query); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	if err := h.validator.Struct(query); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	assessments, errRes := h.footprintUsecase.GetAssessments(userID, *query)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, assessments)
+}
+
+func getUserIDFromContext(ctx *fiber.Ctx) (uuid.UUID, *res.Err) {
+	userIDStr := ctx.Locals("user_id")
+	if userIDStr == nil {
+		return uuid.Nil, res.ErrUnauthorized("User not authenticated")
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		return uuid.Nil, res.ErrUnauthorized("Invalid user ID")
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type FootprintRepositoryItf interface {
+	GetLatestEmissionFactors(region string) ([]entity.EmissionFactor, error)
+	GetLatestEmissionFactor(category entity.EmissionCategory, key, region string) (*entity.EmissionFactor, error)
+	CreateAssessment(assessment *entity.FootprintAssessment) error
+	GetAssessments(userID uuid.UUID, limit, offset int) ([]entity.FootprintAssessment, int64, error)
+}
+
+type FootprintRepository struct {
+	db *gorm.DB
+}
+
+func NewFootprintRepository(db *gorm.DB) FootprintRepositoryItf {
+	return &FootprintRepository{db}
+}
+
+// GetLatestEmissionFactors returns the newest version of every global factor
+// together with the newest version of those specific to region. A version may
+// only update some factors, so the newest version is resolved per category,
+// key and region rather than across the whole table.
+func (r *FootprintRepository) GetLatestEmissionFactors(region string) ([]entity.EmissionFactor, error) {
+	var factors []entity.EmissionFactor
+	err := r.db.Select("DISTINCT ON (category, key, region) *").
+		Where("region IN ?", []string{entity.GlobalRegion, region}).
+		Order("category ASC, key ASC, region ASC, version DESC").
+		Find(&factors).Error
+
+	return factors, err
+}
+
+// GetLatestEmissionFactor prefers the newest factor specific to region and
+// falls back to the newest global one.
+func (r *FootprintRepository) GetLatestEmissionFactor(category entity.EmissionCategory, key, region string) (*entity.EmissionFactor, error) {
+	var factor entity.EmissionFactor
+	err := r.db.Where("category = ? AND key = ? AND region IN ?", category, key, []string{entity.GlobalRegion, region}).
+		Order(gorm.Expr("region = ? ASC", entity.GlobalRegion)).
+		Order("version DESC").
+		First(&factor).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &factor, nil
+}
+
+func (r *FootprintRepository) CreateAssessment(assessment *entity.FootprintAssessment) error {
+	return r.db.Create(assessment).Error
+}
+
+func (r *FootprintRepository) GetAssessments(userID uuid.UUID, limit, offset int) ([]entity.FootprintAssessment, int64, error) {
+	query := r.db.Model(&entity.FootprintAssessment{}).Where("user_id = ?", userID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var assessments []entity.FootprintAssessment
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&assessments).Error
+
+	return assessments, total, err
+}
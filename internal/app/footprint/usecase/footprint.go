@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+
+	"github.com/Ablebil/eco-sample/config"
+	footprintRepository "github.com/Ablebil/eco-sample/internal/app/footprint/repository"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/google/uuid"
+)
+
+type FootprintUsecaseItf interface {
+	GetEmissionFactors(region string) (*dto.GetEmissionFactorsResponse, *res.Err)
+	CreateAssessment(userID uuid.UUID, req dto.FootprintAssessmentRequest) (*dto.FootprintAssessmentResponse, *res.Err)
+	GetAssessments(userID uuid.UUID, query dto.GetAssessmentsQuery) (*dto.GetAssessmentsResponse, *res.Err)
+}
+
+type FootprintUsecase struct {
+	footprintRepository footprintRepository.FootprintRepositoryItf
+	cfg                 *config.Config
+}
+
+func NewFootprintUsecase(footprintRepository footprintRepository.FootprintRepositoryItf, cfg *config.Config) FootprintUsecaseItf {
+	return &FootprintUsecase{
+		footprintRepository: footprintRepository,
+		cfg:                 cfg,
+	}
+}
+
+const (
+	weeksPerYear  = 52
+	monthsPerYear = 12
+
+	defaultAssessmentsLimit = 20
+)
+
+type factorKey struct {
+	category entity.EmissionCategory
+	key      string
+}
+
+func (uc *FootprintUsecase) GetEmissionFactors(region string) (*dto.GetEmissionFactorsResponse, *res.Err) {
+	version, factors, errRes := uc.loadFactors(uc.normalizeRegion(region))
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	response := &dto.GetEmissionFactorsResponse{
+		Version: version,
+		Factors: make([]dto.EmissionFactorResponse, 0, len(factors)),
+	}
+
+	for _, factor := range factors {
+		response.Factors = append(response.Factors, dto.EmissionFactorResponse{
+			Category: string(factor.Category),
+			Key:      factor.Key,
+			Region:   factor.Region,
+			Unit:     factor.Unit,
+			Factor:   factor.Factor,
+			Source:   factor.Source,
+		})
+	}
+
+	return response, nil
+}
+
+func (uc *FootprintUsecase) CreateAssessment(userID uuid.UUID, req dto.FootprintAssessmentRequest) (*dto.FootprintAssessmentResponse, *res.Err) {
+	req.Region = uc.normalizeRegion(req.Region)
+
+	version, factors, errRes := uc.loadFactors(req.Region)
+	if errRes != nil {
+		return nil, errRes
+	}
+
+	factorByKey := make(map[factorKey]entity.EmissionFactor, len(factors))
+	for _, factor := range factors {
+		key := factorKey{factor.Category, factor.Key}
+		// A region-specific factor always wins over the global one.
+		if existing, ok := factorByKey[key]; ok && existing.Region != entity.GlobalRegion {
+			continue
+		}
+		factorByKey[key] = factor
+	}
+
+	var breakdown dto.FootprintBreakdown
+
+	for _, usage := range req.Transport {
+		factor, ok := factorByKey[factorKey{entity.EmissionTransport, usage.Mode}]
+		if !ok {
+			return nil, res.ErrBadRequest(res.UnsupportedTransportMode)
+		}
+		breakdown.TransportKg += usage.KmPerWeek * weeksPerYear * factor.Factor
+	}
+
+	if req.ElectricityKwhPerMonth > 0 {
+		factor, ok := factorByKey[factorKey{entity.EmissionElectricity, "grid"}]
+		if !ok {
+			return nil, res.ErrBadRequest(res.UnsupportedRegion)
+		}
+		breakdown.ElectricityKg = req.ElectricityKwhPerMonth * monthsPerYear * factor.Factor
+	}
+
+	diet, ok := factorByKey[factorKey{entity.EmissionDiet, req.Diet}]
+	if !ok {
+		return nil, res.ErrBadRequest(res.UnsupportedDiet)
+	}
+	breakdown.DietKg = diet.Factor
+
+	if req.WasteKgPerWeek > 0 {
+		landfill, hasLandfill := factorByKey[factorKey{entity.EmissionWaste, "landfill"}]
+		recycled, hasRecycled := factorByKey[factorKey{entity.EmissionWaste, "recycled"}]
+		if !hasLandfill || !hasRecycled {
+			return nil, res.ErrInternalServerError(res.EmissionFactorsUnavailable)
+		}
+
+		recycledShare := float64(req.RecycledPercent) / 100
+		perKg := landfill.Factor*(1-recycledShare) + recycled.Factor*recycledShare
+		breakdown.WasteKg = req.WasteKgPerWeek * weeksPerYear * perKg
+	}
+
+	breakdown.TransportKg = round2(breakdown.TransportKg)
+	breakdown.ElectricityKg = round2(breakdown.ElectricityKg)
+	breakdown.DietKg = round2(breakdown.DietKg)
+	breakdown.WasteKg = round2(breakdown.WasteKg)
+
+	answers, err := json.Marshal(req)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedCreateAssessment)
+	}
+
+	assessment := &entity.FootprintAssessment{
+		UserID:        userID,
+		Region:        req.Region,
+		FactorVersion: version,
+		Answers:       string(answers),
+		TransportKg:   breakdown.TransportKg,
+		ElectricityKg: breakdown.ElectricityKg,
+		DietKg:        breakdown.DietKg,
+		WasteKg:       breakdown.WasteKg,
+		TotalKg:       round2(breakdown.TransportKg + breakdown.ElectricityKg + breakdown.DietKg + breakdown.WasteKg),
+	}
+
+	if err := uc.footprintRepository.CreateAssessment(assessment); err != nil {
+		return nil, res.ErrInternalServerError(res.FailedCreateAssessment)
+	}
+
+	response := toAssessmentResponse(assessment)
+	return &response, nil
+}
+
+func (uc *FootprintUsecase) GetAssessments(userID uuid.UUID, query dto.GetAssessmentsQuery) (*dto.GetAssessmentsResponse, *res.Err) {
+	page := max(query.Page, 1)
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultAssessmentsLimit
+	}
+
+	assessments, total, err := uc.footprintRepository.GetAssessments(userID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetAssessments)
+	}
+
+	response := &dto.GetAssessmentsResponse{
+		Assessments: make([]dto.FootprintAssessmentResponse, 0, len(assessments)),
+		Page:        page,
+		Limit:       limit,
+		Total:       total,
+	}
+
+	for _, assessment := range assessments {
+		response.Assessments = append(response.Assessments, toAssessmentResponse(&assessment))
+	}
+
+	return response, nil
+}
+
+// loadFactors also returns the newest version among the factors, which
+// identifies the set since every factor resolves to its newest version up to it.
+func (uc *FootprintUsecase) loadFactors(region string) (int, []entity.EmissionFactor, *res.Err) {
+	factors, err := uc.footprintRepository.GetLatestEmissionFactors(region)
+	if err != nil {
+		return 0, nil, res.ErrInternalServerError(res.FailedGetEmissionFactors)
+	}
+
+	if len(factors) == 0 {
+		return 0, nil, res.ErrInternalServerError(res.EmissionFactorsUnavailable)
+	}
+
+	version := 0
+	for _, factor := range factors {
+		version = max(version, factor.Version)
+	}
+
+	return version, factors, nil
+}
+
+func (uc *FootprintUsecase) normalizeRegion(region string) string {
+	if region == "" {
+		region = uc.cfg.FootprintDefaultRegion
+	}
+
+	return strings.ToUpper(region)
+}
+
+func toAssessmentResponse(assessment *entity.FootprintAssessment) dto.FootprintAssessmentResponse {
+	response := dto.FootprintAssessmentResponse{
+		ID:            assessment.ID,
+		Region:        assessment.Region,
+		FactorVersion: assessment.FactorVersion,
+		AnnualKg:      assessment.TotalKg,
+		Breakdown: dto.FootprintBreakdown{
+			TransportKg:   assessment.TransportKg,
+			ElectricityKg: assessment.ElectricityKg,
+			DietKg:        assessment.DietKg,
+			WasteKg:       assessment.WasteKg,
+		},
+	}
+
+	if assessment.CreatedAt != nil {
+		response.CreatedAt = *assessment.CreatedAt
+	}
+
+	return response
+}
+
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
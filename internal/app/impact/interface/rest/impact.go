@@ -0,0 +1,50 @@
+package rest
+
+import (
+	"github.com/Ablebil/eco-sample/internal/app/impact/usecase"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type ImpactHandler struct {
+	impactUsecase usecase.ImpactUsecaseItf
+}
+
+func NewImpactHandler(impactGroup fiber.Router, impactUsecase usecase.ImpactUsecaseItf, middleware middleware.MiddlewareItf) {
+	impactHandler := ImpactHandler{
+		impactUsecase: impactUsecase,
+	}
+
+	impactGroup = impactGroup.Group("/impact")
+	impactGroup.Get("/me", middleware.Authentication, impactHandler.GetMyImpact)
+}
+
+func (h *ImpactHandler) GetMyImpact(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	impact, errRes := h.impactUsecase.GetMyImpact(userID)
+	if errRes != nil {
+		return errRes
+	}
+
+	return res.OK(ctx, impact)
+}
+
+func getUserIDFromContext(ctx *fiber.Ctx) (uuid.UUID, *res.Err) {
+	userIDStr := ctx.Locals("user_id")
+	if userIDStr == nil {
+		return uuid.Nil, res.ErrUnauthorized("User not authenticated")
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		return uuid.Nil, res.ErrUnauthorized("Invalid user ID")
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ImpactRepositoryItf interface {
+	GetUserByID(id uuid.UUID) (*entity.User, error)
+	GetImpactRecords(userID uuid.UUID, since time.Time) ([]entity.ImpactRecord, error)
+}
+
+type ImpactRepository struct {
+	db *gorm.DB
+}
+
+func NewImpactRepository(db *gorm.DB) ImpactRepositoryItf {
+	return &ImpactRepository{db}
+}
+
+func (r *ImpactRepository) GetUserByID(id uuid.UUID) (*entity.User, error) {
+	var user entity.User
+	err := r.db.Where("id = ?", id).First(&user).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *ImpactRepository) GetImpactRecords(userID uuid.UUID, since time.Time) ([]entity.ImpactRecord, error) {
+	var records []entity.ImpactRecord
+	err := r.db.Where("user_id = ? AND recorded_at >= ?", userID, since).
+		Order("recorded_at ASC").
+		Find(&records).Error
+
+	return records, err
+}
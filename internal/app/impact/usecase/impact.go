@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ablebil/eco-sample/config"
+	impactRepository "github.com/Ablebil/eco-sample/internal/app/impact/repository"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/google/uuid"
+)
+
+type ImpactUsecaseItf interface {
+	GetMyImpact(userID uuid.UUID) (*dto.GetImpactResponse, *res.Err)
+}
+
+type ImpactUsecase struct {
+	impactRepository impactRepository.ImpactRepositoryItf
+	location         *time.Location
+}
+
+func NewImpactUsecase(impactRepository impactRepository.ImpactRepositoryItf, cfg *config.Config) ImpactUsecaseItf {
+	return &ImpactUsecase{
+		impactRepository: impactRepository,
+		location:         cfg.Location,
+	}
+}
+
+const (
+	dailyBuckets   = 7
+	weeklyBuckets  = 8
+	monthlyBuckets = 12
+)
+
+func (uc *ImpactUsecase) GetMyImpact(userID uuid.UUID) (*dto.GetImpactResponse, *res.Err) {
+	user, err := uc.impactRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, res.ErrNotFound(res.UserNotFound)
+	}
+
+	now := time.Now().In(uc.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, uc.location)
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	firstOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, uc.location)
+
+	var daily, weekly, monthly []time.Time
+	for i := dailyBuckets - 1; i >= 0; i-- {
+		daily = append(daily, today.AddDate(0, 0, -i))
+	}
+	for i := weeklyBuckets - 1; i >= 0; i-- {
+		weekly = append(weekly, monday.AddDate(0, 0, -7*i))
+	}
+	for i := monthlyBuckets - 1; i >= 0; i-- {
+		monthly = append(monthly, firstOfMonth.AddDate(0, -i, 0))
+	}
+
+	since := minTime(daily[0], weekly[0], monthly[0])
+	records, err := uc.impactRepository.GetImpactRecords(userID, since)
+	if err != nil {
+		return nil, res.ErrInternalServerError(res.FailedGetImpact)
+	}
+
+	return &dto.GetImpactResponse{
+		Total: dto.ImpactTotals{
+			Co2eKg:       user.TotalCo2eKg,
+			WaterLiters:  user.TotalWaterLiters,
+			PlasticItems: user.TotalPlasticItems,
+		},
+		Daily:   uc.breakdown(records, daily, dayKey),
+		Weekly:  uc.breakdown(records, weekly, weekKey),
+		Monthly: uc.breakdown(records, monthly, monthKey),
+	}, nil
+}
+
+// breakdown sums records into one bucket per period start, keeping empty
+// periods so clients can chart a continuous series.
+func (uc *ImpactUsecase) breakdown(records []entity.ImpactRecord, starts []time.Time, key func(time.Time) string) []dto.ImpactBucket {
+	buckets := make([]dto.ImpactBucket, len(starts))
+	index := make(map[string]int, len(starts))
+	for i, start := range starts {
+		buckets[i].Period = key(start)
+		index[buckets[i].Period] = i
+	}
+
+	for _, record := range records {
+		i, ok := index[key(record.RecordedAt.In(uc.location))]
+		if !ok {
+			continue
+		}
+
+		buckets[i].CompletedChallenges++
+		buckets[i].Co2eKg += record.Co2eKg
+		buckets[i].WaterLiters += record.WaterLiters
+		buckets[i].PlasticItems += record.PlasticItems
+	}
+
+	return buckets
+}
+
+func dayKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+func weekKey(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+func monthKey(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+func minTime(times ...time.Time) time.Time {
+	earliest := times[0]
+	for _, t := range times[1:] {
+		if t.Before(earliest) {
+			earliest = t
+		}
+	}
+
+	return earliest
+}
@@ -0,0 +1,112 @@
+package rest
+
+import (
+	"github.com/Ablebil/eco-sample/internal/app/user/usecase"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/Ablebil/eco-sample/internal/middleware"
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type UserHandler struct {
+	validator   *validator.Validate
+	userUsecase usecase.UserUsecaseItf
+}
+
+func NewUserHandler(userGroup, adminGroup fiber.Router, validator *validator.Validate, userUsecase usecase.UserUsecaseItf, middleware middleware.MiddlewareItf) {
+	userHandler := UserHandler{
+		validator:   validator,
+		userUsecase: userUsecase,
+	}
+
+	userGroup = userGroup.Group("/users")
+	userGroup.Patch("/me/password", middleware.Authentication, userHandler.ChangePassword)
+
+	adminGroup = adminGroup.Group("/users")
+	adminGroup.Patch("/:id/role", userHandler.UpdateUserRole)
+}
+
+func (h *UserHandler) ChangePassword(ctx *fiber.Ctx) error {
+	userID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.ChangePasswordRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	tokens, changed, errRes := h.userUsecase.ChangePassword(userID, *req)
+	if errRes != nil {
+		return errRes
+	}
+
+	message := res.ChangePasswordSuccess
+	if !changed {
+		message = res.SetPasswordSuccess
+	}
+
+	if tokens == nil {
+		return res.OK(ctx, nil, message)
+	}
+
+	return res.OK(ctx, tokens, message)
+}
+
+func (h *UserHandler) UpdateUserRole(ctx *fiber.Ctx) error {
+	actorID, err := getUserIDFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	userID, parseErr := uuid.Parse(ctx.Params("id"))
+	if parseErr != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestParams)
+	}
+
+	req := new(dto.UpdateRoleRequest)
+	if err := ctx.BodyParser(req); err != nil {
+		return res.ErrBadRequest(res.FailedParsingRequestBody)
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors, ok := err.(validator.ValidationErrors)
+		if !ok {
+			return res.ErrInternalServerError(res.FailedValidateRequest)
+		}
+
+		return res.ErrValidation(validationErrors)
+	}
+
+	if err := h.userUsecase.UpdateUserRole(actorID, userID, *req); err != nil {
+		return err
+	}
+
+	return res.OK(ctx, nil, res.UpdateRoleSuccess)
+}
+
+func getUserIDFromContext(ctx *fiber.Ctx) (uuid.UUID, *res.Err) {
+	userIDStr := ctx.Locals("user_id")
+	if userIDStr == nil {
+		return uuid.Nil, res.ErrUnauthorized("User not authenticated")
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		return uuid.Nil, res.ErrUnauthorized("Invalid user ID")
+	}
+
+	return userID, nil
+}
@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"time"
 
 	"github.com/Ablebil/eco-sample/internal/domain/entity"
 	"github.com/google/uuid"
@@ -10,13 +13,25 @@ import (
 
 type UserRepositoryItf interface {
 	GetUserByEmail(email string) (*entity.User, error)
-	GetUserByRefreshToken(refreshToken string) (*entity.User, error)
 	GetUserByID(id uuid.UUID) (*entity.User, error)
 	CreateUser(user *entity.User) error
-	UpdateUser(email string, user *entity.User) error
-	AddRefreshToken(userId uuid.UUID, token string) error
+	UpdateUser(id uuid.UUID, user *entity.User) error
+	UpdateUserEmail(id uuid.UUID, email string) error
+	UpdateUserRole(id uuid.UUID, role entity.Role) error
+	AddRefreshToken(refreshToken *entity.RefreshToken) error
+	GetRefreshToken(token string) (*entity.RefreshToken, error)
+	GetRefreshTokenByID(id uuid.UUID) (*entity.RefreshToken, error)
 	GetRefreshTokens(userId uuid.UUID) ([]entity.RefreshToken, error)
-	RemoveRefreshToken(token string) error
+	ConsumeRefreshToken(id uuid.UUID) (bool, error)
+	RemoveRefreshTokenFamily(familyID uuid.UUID) error
+	RemoveConsumedRefreshTokens(familyID uuid.UUID, before time.Time) error
+	RemoveRefreshTokensByUserID(userId uuid.UUID) error
+	CreateSecurityEvent(event *entity.SecurityEvent) error
+	UpdateUserMFA(id uuid.UUID, totpSecret *string, enabled bool) error
+	ReplaceRecoveryCodes(userId uuid.UUID, codeHashes []string) error
+	UseRecoveryCode(userId uuid.UUID, codeHash string) (bool, error)
+	UseTOTPStep(id uuid.UUID, step int64) (bool, error)
+	RemoveRecoveryCodes(userId uuid.UUID) error
 }
 
 type UserRepository struct {
@@ -42,9 +57,53 @@ func (r *UserRepository) GetUserByEmail(email string) (*entity.User, error) {
 	return &user, nil
 }
 
-func (r *UserRepository) GetUserByRefreshToken(token string) (*entity.User, error) {
+func (r *UserRepository) GetUserByID(id uuid.UUID) (*entity.User, error) {
+	var user entity.User
+	err := r.db.Where("id = ?", id).First(&user).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *UserRepository) CreateUser(user *entity.User) error {
+	return r.db.Create(user).Error
+}
+
+func (r *UserRepository) UpdateUser(id uuid.UUID, user *entity.User) error {
+	return r.db.Model(&entity.User{}).
+		Where("id = ?", id).
+		Updates(user).Error
+}
+
+func (r *UserRepository) UpdateUserEmail(id uuid.UUID, email string) error {
+	return r.db.Model(&entity.User{}).
+		Where("id = ?", id).
+		Update("email", email).Error
+}
+
+func (r *UserRepository) UpdateUserRole(id uuid.UUID, role entity.Role) error {
+	return r.db.Model(&entity.User{}).
+		Where("id = ?", id).
+		Update("role", role).Error
+}
+
+// AddRefreshToken stores the refresh token with its Token replaced by a
+// SHA-256 hash, so the raw JWT never reaches the database.
+func (r *UserRepository) AddRefreshToken(refreshToken *entity.RefreshToken) error {
+	refreshToken.Token = hashToken(refreshToken.Token)
+	return r.db.Create(refreshToken).Error
+}
+
+func (r *UserRepository) GetRefreshToken(token string) (*entity.RefreshToken, error) {
 	var refreshToken entity.RefreshToken
-	err := r.db.Preload("User").Where("token = ?", token).First(&refreshToken).Error
+	err := r.db.Preload("User").Where("token = ?", hashToken(token)).First(&refreshToken).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -54,12 +113,12 @@ func (r *UserRepository) GetUserByRefreshToken(token string) (*entity.User, erro
 		return nil, err
 	}
 
-	return refreshToken.User, nil
+	return &refreshToken, nil
 }
 
-func (r *UserRepository) GetUserByID(id uuid.UUID) (*entity.User, error) {
-	var user entity.User
-	err := r.db.Where("id = ?", id).First(&user).Error
+func (r *UserRepository) GetRefreshTokenByID(id uuid.UUID) (*entity.RefreshToken, error) {
+	var refreshToken entity.RefreshToken
+	err := r.db.Where("id = ?", id).First(&refreshToken).Error
 
 	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, nil
@@ -69,32 +128,110 @@ func (r *UserRepository) GetUserByID(id uuid.UUID) (*entity.User, error) {
 		return nil, err
 	}
 
-	return &user, nil
+	return &refreshToken, nil
 }
 
-func (r *UserRepository) CreateUser(user *entity.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepository) GetRefreshTokens(userId uuid.UUID) ([]entity.RefreshToken, error) {
+	var refreshTokens []entity.RefreshToken
+	err := r.db.Where("user_id = ? AND consumed_at IS NULL", userId).
+		Order("COALESCE(last_used_at, created_at) ASC").
+		Find(&refreshTokens).Error
+	return refreshTokens, err
+}
+
+func (r *UserRepository) ConsumeRefreshToken(id uuid.UUID) (bool, error) {
+	result := r.db.Model(&entity.RefreshToken{}).
+		Where("id = ? AND consumed_at IS NULL", id).
+		Update("consumed_at", gorm.Expr("NOW()"))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserRepository) RemoveRefreshTokenFamily(familyID uuid.UUID) error {
+	return r.db.Where("family_id = ?", familyID).Delete(&entity.RefreshToken{}).Error
+}
+
+func (r *UserRepository) RemoveConsumedRefreshTokens(familyID uuid.UUID, before time.Time) error {
+	return r.db.Where("family_id = ? AND consumed_at IS NOT NULL AND consumed_at < ?", familyID, before).
+		Delete(&entity.RefreshToken{}).Error
+}
+
+func (r *UserRepository) RemoveRefreshTokensByUserID(userId uuid.UUID) error {
+	return r.db.Where("user_id = ?", userId).Delete(&entity.RefreshToken{}).Error
+}
+
+func (r *UserRepository) CreateSecurityEvent(event *entity.SecurityEvent) error {
+	return r.db.Create(event).Error
 }
 
-func (r *UserRepository) UpdateUser(email string, user *entity.User) error {
+func (r *UserRepository) UpdateUserMFA(id uuid.UUID, totpSecret *string, enabled bool) error {
+	updates := map[string]interface{}{
+		"totp_secret": totpSecret,
+		"mfa_enabled": enabled,
+	}
+
+	if !enabled {
+		updates["totp_last_step"] = 0
+	}
+
 	return r.db.Model(&entity.User{}).
-		Where("email = ?", email).
-		Updates(user).Error
+		Where("id = ?", id).
+		Updates(updates).Error
 }
 
-func (r *UserRepository) AddRefreshToken(userId uuid.UUID, token string) error {
-	return r.db.Create(&entity.RefreshToken{
-		Token:  token,
-		UserID: userId,
-	}).Error
+func (r *UserRepository) ReplaceRecoveryCodes(userId uuid.UUID, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userId).Delete(&entity.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]entity.MFARecoveryCode, 0, len(codeHashes))
+		for _, codeHash := range codeHashes {
+			codes = append(codes, entity.MFARecoveryCode{
+				UserID:   userId,
+				CodeHash: codeHash,
+			})
+		}
+
+		return tx.Create(&codes).Error
+	})
 }
 
-func (r *UserRepository) GetRefreshTokens(userId uuid.UUID) ([]entity.RefreshToken, error) {
-	var refreshTokens []entity.RefreshToken
-	err := r.db.Where("user_id = ?", userId).Find(&refreshTokens).Error
-	return refreshTokens, err
+func (r *UserRepository) UseRecoveryCode(userId uuid.UUID, codeHash string) (bool, error) {
+	result := r.db.Model(&entity.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", gorm.Expr("NOW()"))
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// UseTOTPStep records step as the last accepted TOTP time step. It reports
+// false when an equal or later step was already accepted.
+func (r *UserRepository) UseTOTPStep(id uuid.UUID, step int64) (bool, error) {
+	result := r.db.Model(&entity.User{}).
+		Where("id = ? AND totp_last_step < ?", id, step).
+		Update("totp_last_step", step)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *UserRepository) RemoveRecoveryCodes(userId uuid.UUID) error {
+	return r.db.Where("user_id = ?", userId).Delete(&entity.MFARecoveryCode{}).Error
 }
 
-func (r *UserRepository) RemoveRefreshToken(token string) error {
-	return r.db.Where("token = ?", token).Delete(&entity.RefreshToken{}).Error
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	authUsecase "github.com/Ablebil/eco-sample/internal/app/auth/usecase"
+	userRepository "github.com/Ablebil/eco-sample/internal/app/user/repository"
+	"github.com/Ablebil/eco-sample/internal/domain/dto"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
+	"github.com/Ablebil/eco-sample/internal/infra/jwt"
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserUsecaseItf interface {
+	ChangePassword(userID uuid.UUID, req dto.ChangePasswordRequest) (*dto.TokenResponse, bool, *res.Err)
+	UpdateUserRole(actorID, userID uuid.UUID, req dto.UpdateRoleRequest) *res.Err
+}
+
+type UserUsecase struct {
+	userRepository userRepository.UserRepositoryItf
+	authUsecase    authUsecase.AuthUsecaseItf
+	jwt            jwt.JWTItf
+}
+
+func NewUserUsecase(userRepository userRepository.UserRepositoryItf, authUsecase authUsecase.AuthUsecaseItf, jwt jwt.JWTItf) UserUsecaseItf {
+	return &UserUsecase{
+		userRepository: userRepository,
+		authUsecase:    authUsecase,
+		jwt:            jwt,
+	}
+}
+
+// ChangePassword reports whether the user already had a password. When other
+// sessions are logged out the caller's access token is revoked as well, so a
+// fresh one is returned for the current session.
+func (uc *UserUsecase) ChangePassword(userID uuid.UUID, req dto.ChangePasswordRequest) (*dto.TokenResponse, bool, *res.Err) {
+	user, err := uc.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, false, res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return nil, false, res.ErrNotFound(res.UserNotFound)
+	}
+
+	hasPassword := user.Password != nil
+	if hasPassword {
+		if req.CurrentPassword == "" {
+			return nil, false, res.ErrBadRequest(res.CurrentPasswordRequired)
+		}
+
+		if bcrypt.CompareHashAndPassword([]byte(*user.Password), []byte(req.CurrentPassword)) != nil {
+			return nil, false, res.ErrUnauthorized(res.InvalidCurrentPassword)
+		}
+	} else if user.GoogleID == nil {
+		return nil, false, res.ErrBadRequest(res.CurrentPasswordRequired)
+	}
+
+	// The caller's own session is kept when the others are logged out, so it
+	// must be identified before anything changes.
+	var current *entity.RefreshToken
+	if req.LogoutOtherSessions {
+		tokenUserID, _, err := uc.jwt.VerifyRefreshToken(req.RefreshToken)
+		if err != nil || tokenUserID != user.ID {
+			return nil, false, res.ErrUnauthorized(res.InvalidRefreshToken)
+		}
+
+		current, err = uc.userRepository.GetRefreshToken(req.RefreshToken)
+		if err != nil {
+			return nil, false, res.ErrInternalServerError(res.FailedGetRefreshTokens)
+		}
+
+		if current == nil || current.UserID != user.ID || current.ConsumedAt != nil {
+			return nil, false, res.ErrUnauthorized(res.InvalidRefreshToken)
+		}
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, false, res.ErrInternalServerError(res.FailedHashPassword)
+	}
+
+	hashedPassword := string(hashed)
+
+	if err := uc.userRepository.UpdateUser(user.ID, &entity.User{
+		Password: &hashedPassword,
+	}); err != nil {
+		return nil, false, res.ErrInternalServerError(res.FailedUpdateUser)
+	}
+
+	if current != nil {
+		refreshTokens, err := uc.userRepository.GetRefreshTokens(user.ID)
+		if err != nil {
+			return nil, false, res.ErrInternalServerError(res.FailedGetRefreshTokens)
+		}
+
+		for _, refreshToken := range refreshTokens {
+			if refreshToken.FamilyID == current.FamilyID {
+				continue
+			}
+
+			if err := uc.userRepository.RemoveRefreshTokenFamily(refreshToken.FamilyID); err != nil {
+				return nil, false, res.ErrInternalServerError(res.FailedRemoveRefreshToken)
+			}
+		}
+
+		// Access tokens of the removed sessions would otherwise stay usable
+		// until they expire.
+		if errRes := uc.authUsecase.RevokeAccessTokens(user.ID); errRes != nil {
+			return nil, false, errRes
+		}
+
+		accessToken, err := uc.jwt.GenerateAccessToken(user.ID, user.Name, user.Email, string(user.Role))
+		if err != nil {
+			return nil, false, res.ErrInternalServerError(res.FailedGenerateAccessToken)
+		}
+
+		return &dto.TokenResponse{AccessToken: accessToken}, hasPassword, nil
+	}
+
+	return nil, hasPassword, nil
+}
+
+func (uc *UserUsecase) UpdateUserRole(actorID, userID uuid.UUID, req dto.UpdateRoleRequest) *res.Err {
+	if actorID == userID {
+		return res.ErrForbidden(res.CannotChangeOwnRole)
+	}
+
+	user, err := uc.userRepository.GetUserByID(userID)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedFindUser)
+	}
+
+	if user == nil {
+		return res.ErrNotFound(res.UserNotFound)
+	}
+
+	role := entity.Role(req.Role)
+	if user.Role == role {
+		return nil
+	}
+
+	if err := uc.userRepository.UpdateUserRole(user.ID, role); err != nil {
+		return res.ErrInternalServerError(res.FailedUpdateRole)
+	}
+
+	// The role travels in the access token, so tokens carrying the old role
+	// must stop working; the user picks up the new one on refresh.
+	return uc.authUsecase.RevokeAccessTokens(user.ID)
+}
@@ -1,19 +1,29 @@
 package bootstrap
 
 import (
+	"errors"
 	"fmt"
 	"log"
 
 	"github.com/Ablebil/eco-sample/config"
+	"github.com/Ablebil/eco-sample/internal/domain/entity"
 	"github.com/Ablebil/eco-sample/internal/infra/email"
 	"github.com/Ablebil/eco-sample/internal/infra/fiber"
 	"github.com/Ablebil/eco-sample/internal/infra/jwt"
+	"github.com/Ablebil/eco-sample/internal/infra/level"
 	"github.com/Ablebil/eco-sample/internal/infra/oauth"
 	"github.com/Ablebil/eco-sample/internal/infra/postgresql"
 	"github.com/Ablebil/eco-sample/internal/infra/redis"
+	"github.com/Ablebil/eco-sample/internal/infra/scheduler"
+	"github.com/Ablebil/eco-sample/internal/infra/storage"
+	"github.com/Ablebil/eco-sample/internal/infra/totp"
 	"github.com/Ablebil/eco-sample/internal/middleware"
 	"github.com/go-playground/validator/v10"
 
+	ActivityHandler "github.com/Ablebil/eco-sample/internal/app/activity/interface/rest"
+	ActivityRepository "github.com/Ablebil/eco-sample/internal/app/activity/repository"
+	ActivityUsecase "github.com/Ablebil/eco-sample/internal/app/activity/usecase"
+
 	AuthHandler "github.com/Ablebil/eco-sample/internal/app/auth/interface/rest"
 	AuthUsecase "github.com/Ablebil/eco-sample/internal/app/auth/usecase"
 
@@ -21,7 +31,17 @@ import (
 	ChallengeRepository "github.com/Ablebil/eco-sample/internal/app/challenge/repository"
 	ChallengeUsecase "github.com/Ablebil/eco-sample/internal/app/challenge/usecase"
 
+	FootprintHandler "github.com/Ablebil/eco-sample/internal/app/footprint/interface/rest"
+	FootprintRepository "github.com/Ablebil/eco-sample/internal/app/footprint/repository"
+	FootprintUsecase "github.com/Ablebil/eco-sample/internal/app/footprint/usecase"
+
+	ImpactHandler "github.com/Ablebil/eco-sample/internal/app/impact/interface/rest"
+	ImpactRepository "github.com/Ablebil/eco-sample/internal/app/impact/repository"
+	ImpactUsecase "github.com/Ablebil/eco-sample/internal/app/impact/usecase"
+
+	UserHandler "github.com/Ablebil/eco-sample/internal/app/user/interface/rest"
 	UserRepository "github.com/Ablebil/eco-sample/internal/app/user/repository"
+	UserUsecase "github.com/Ablebil/eco-sample/internal/app/user/usecase"
 )
 
 func Start() error {
@@ -50,25 +70,87 @@ func Start() error {
 		log.Printf("Failed to seed database: %v", err)
 	}
 
+	if err := postgresql.SeedAdmins(db, cfg.AdminEmails); err != nil {
+		log.Printf("Failed to seed admins: %v", err)
+	}
+
 	validator := validator.New()
-	jwt := jwt.NewJWT(cfg)
+	jwt, err := jwt.NewJWT(cfg)
+	if err != nil {
+		return err
+	}
+
 	email := email.NewEmail(cfg)
 	redis := redis.NewRedis(cfg)
 	oauth := oauth.NewOAuth(cfg)
-	middleware := middleware.NewMiddleware(jwt)
+	mfa, err := totp.NewTOTP(cfg)
+	if errors.Is(err, totp.ErrMissingEncryptionKey) {
+		log.Println("MFA_ENCRYPTION_KEY is not set; MFA is disabled")
+	} else if err != nil {
+		return err
+	}
+
+	proofStorage := storage.NewPrivateStorage(cfg)
+	storage := storage.NewLocalStorage(cfg)
+	scheduler := scheduler.NewScheduler()
+	middleware := middleware.NewMiddleware(jwt, redis, cfg)
+	levelCurve, err := level.NewCurve(cfg)
+	if err != nil {
+		return err
+	}
 
 	app := fiber.New(cfg)
+	app.Static(cfg.StoragePublicPath, cfg.StorageDir)
 	v1 := app.Group("/api/v1")
+	admin := v1.Group("/admin", middleware.Authentication, middleware.Authorize(string(entity.RoleAdmin)))
+	moderation := v1.Group("/moderation", middleware.Authentication, middleware.Authorize(string(entity.RoleModerator), string(entity.RoleAdmin)))
 
 	// Auth Domain
 	userRepository := UserRepository.NewUserRepository(db)
-	authUsecase := AuthUsecase.NewAuthUsecase(userRepository, cfg, jwt, email, redis, oauth)
-	AuthHandler.NewAuthHandler(v1, validator, authUsecase, cfg)
+	authUsecase := AuthUsecase.NewAuthUsecase(userRepository, cfg, jwt, email, redis, oauth, mfa)
+	AuthHandler.NewAuthHandler(v1, validator, authUsecase, cfg, middleware)
+	AuthHandler.NewWellKnownHandler(app, authUsecase)
+
+	// User Domain
+	userUsecase := UserUsecase.NewUserUsecase(userRepository, authUsecase, jwt)
+	UserHandler.NewUserHandler(v1, admin, validator, userUsecase, middleware)
+
+	// Footprint Domain
+	footprintRepository := FootprintRepository.NewFootprintRepository(db)
+	footprintUsecase := FootprintUsecase.NewFootprintUsecase(footprintRepository, cfg)
+	FootprintHandler.NewFootprintHandler(v1, validator, footprintUsecase, middleware)
+
+	// Activity Domain
+	activityRepository := ActivityRepository.NewActivityRepository(db)
+	activityUsecase := ActivityUsecase.NewActivityUsecase(activityRepository, footprintRepository, cfg)
+	ActivityHandler.NewActivityHandler(v1, validator, activityUsecase, middleware)
 
 	// Challenge Domain
 	challengeRepository := ChallengeRepository.NewChallengeRepository(db)
-	challengeUsecase := ChallengeUsecase.NewChallengeUsecase(challengeRepository)
-	ChallengeHandler.NewChallengeHandler(v1, validator, challengeUsecase, middleware)
+	challengeUsecase := ChallengeUsecase.NewChallengeUsecase(challengeRepository, activityUsecase, storage, proofStorage, levelCurve, cfg)
+	ChallengeHandler.NewChallengeHandler(v1, admin, moderation, validator, challengeUsecase, middleware)
+	scheduler.Every("fail-overdue-challenges", cfg.ChallengeExpiryInterval, func() error {
+		if _, errRes := challengeUsecase.FailOverdueChallenges(); errRes != nil {
+			return errRes
+		}
+
+		return nil
+	})
+	scheduler.Every("backfill-badges", cfg.BadgeBackfillInterval, func() error {
+		if _, errRes := challengeUsecase.BackfillPendingBadges(); errRes != nil {
+			return errRes
+		}
+
+		return nil
+	})
+
+	// Impact Domain
+	impactRepository := ImpactRepository.NewImpactRepository(db)
+	impactUsecase := ImpactUsecase.NewImpactUsecase(impactRepository, cfg)
+	ImpactHandler.NewImpactHandler(v1, impactUsecase, middleware)
+
+	scheduler.Start()
+	defer scheduler.Stop()
 
 	return app.Listen(fmt.Sprintf("%s:%d", cfg.AppHost, cfg.AppPort))
 }
@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CreateActivityRequest struct {
+	Type       string  `json:"type" validate:"required,oneof=transport electricity"`
+	Mode       string  `json:"mode" validate:"required_if=Type transport,excluded_unless=Type transport,max=50"`
+	Quantity   float64 `json:"quantity" validate:"gt=0"`
+	OccurredOn string  `json:"occurred_on" validate:"omitempty,datetime=2006-01-02"`
+	Note       *string `json:"note" validate:"omitempty,max=500"`
+}
+
+type UpdateActivityRequest struct {
+	Mode       *string  `json:"mode" validate:"omitempty,min=1,max=50"`
+	Quantity   *float64 `json:"quantity" validate:"omitempty,gt=0"`
+	OccurredOn *string  `json:"occurred_on" validate:"omitempty,datetime=2006-01-02"`
+	Note       *string  `json:"note" validate:"omitempty,max=500"`
+}
+
+type GetActivitiesQuery struct {
+	Page  int    `query:"page" validate:"omitempty,gte=1"`
+	Limit int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	Type  string `query:"type" validate:"omitempty,oneof=transport electricity"`
+	From  string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To    string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type ActivityResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Type          string    `json:"type"`
+	Mode          *string   `json:"mode,omitempty"`
+	Quantity      float64   `json:"quantity"`
+	Unit          string    `json:"unit"`
+	Co2eKg        float64   `json:"co2e_kg"`
+	Region        string    `json:"region"`
+	FactorVersion int       `json:"factor_version"`
+	OccurredOn    string    `json:"occurred_on"`
+	Note          *string   `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type GetActivitiesResponse struct {
+	Activities []ActivityResponse `json:"activities"`
+	Page       int                `json:"page"`
+	Limit      int                `json:"limit"`
+	Total      int64              `json:"total"`
+}
+
+type ActivityDayResponse struct {
+	Date           string  `json:"date"`
+	Entries        int     `json:"entries"`
+	Co2eKg         float64 `json:"co2e_kg"`
+	TransportKm    float64 `json:"transport_km"`
+	ElectricityKwh float64 `json:"electricity_kwh"`
+}
+
+type ActivityTrendResponse struct {
+	Last7DaysKg     float64               `json:"last_7_days_kg"`
+	Previous7DaysKg float64               `json:"previous_7_days_kg"`
+	ChangePercent   *float64              `json:"change_percent"`
+	Daily           []ActivityDayResponse `json:"daily"`
+}
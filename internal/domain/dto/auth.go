@@ -1,5 +1,11 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
 type RegisterRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
@@ -7,14 +13,40 @@ type RegisterRequest struct {
 }
 
 type VerifyOTPRequest struct {
-	Email string `json:"email" validate:"required,email"`
-	OTP   string `json:"otp" validate:"required,len=6,numeric"`
+	Email      string `json:"email" validate:"required,email"`
+	OTP        string `json:"otp" validate:"required,len=6,numeric"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=255"`
 }
 
 type LoginRequest struct {
 	Email      string `json:"email" validate:"required,email"`
 	Password   string `json:"password" validate:"required,min=8"`
 	RememberMe bool   `json:"remember_me"`
+	DeviceName string `json:"device_name" validate:"omitempty,max=255"`
+}
+
+type ResendOTPRequest struct {
+	Email   string `json:"email" validate:"required,email"`
+	Purpose string `json:"purpose" validate:"required,oneof=verify_email reset_password"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" validate:"required,email"`
+	Password string `json:"password"`
+}
+
+type ConfirmChangeEmailRequest struct {
+	OTP string `json:"otp" validate:"required,len=6,numeric"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Email       string `json:"email" validate:"required,email"`
+	OTP         string `json:"otp" validate:"required,len=6,numeric"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
 }
 
 type RefreshTokenRequest struct {
@@ -31,9 +63,44 @@ type GoogleCallbackRequest struct {
 	Error string `json:"error"`
 }
 
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required,min=6,max=16"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,min=6,max=16"`
+}
+
 type TokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFARequired  bool   `json:"mfa_required,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+}
+
+type MFASetupResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type SessionMetadata struct {
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	DeviceName *string    `json:"device_name"`
+	UserAgent  *string    `json:"user_agent"`
+	IPAddress  *string    `json:"ip_address"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  *time.Time `json:"created_at"`
 }
 
 type GoogleProfileResponse struct {
@@ -11,27 +11,167 @@ type TakeChallengeRequest struct {
 }
 
 type CompleteChallengeRequest struct {
+	ChallengeID uuid.UUID `json:"challenge_id" form:"challenge_id" validate:"required,uuid"`
+	Note        string    `json:"note" form:"note" validate:"max=1000"`
+}
+
+type CompleteChallengeResponse struct {
+	Status    string              `json:"status"`
+	NewBadges []GetBadgesResponse `json:"new_badges"`
+	LevelUp   *LevelUpResponse    `json:"level_up"`
+}
+
+type LevelUpResponse struct {
+	PreviousLevel  int `json:"previous_level"`
+	Level          int `json:"level"`
+	ExpToNextLevel int `json:"exp_to_next_level"`
+}
+
+type AbandonChallengeRequest struct {
 	ChallengeID uuid.UUID `json:"challenge_id" validate:"required,uuid"`
 }
 
+type CreateChallengeRequest struct {
+	Title        string  `json:"title" validate:"required,max=255"`
+	Description  *string `json:"description"`
+	ExpReward    int     `json:"exp_reward" validate:"gte=0,lte=10000"`
+	Co2eKg       float64 `json:"co2e_kg" validate:"gte=0,lte=100000"`
+	WaterLiters  float64 `json:"water_liters" validate:"gte=0,lte=1000000"`
+	PlasticItems int     `json:"plastic_items" validate:"gte=0,lte=10000"`
+	DurationDays int     `json:"duration_days" validate:"omitempty,gte=1,lte=365"`
+	Recurrence   string  `json:"recurrence" validate:"omitempty,oneof=none daily weekly monthly"`
+	ProofType    string  `json:"proof_type" validate:"omitempty,oneof=none photo note photo_and_note"`
+	TrackMode    string  `json:"track_mode" validate:"omitempty,oneof=none walking cycling public_transport"`
+	IsActive     *bool   `json:"is_active"`
+}
+
+type UpdateChallengeRequest struct {
+	Title        *string  `json:"title" validate:"omitempty,min=1,max=255"`
+	Description  *string  `json:"description"`
+	ExpReward    *int     `json:"exp_reward" validate:"omitempty,gte=0,lte=10000"`
+	Co2eKg       *float64 `json:"co2e_kg" validate:"omitempty,gte=0,lte=100000"`
+	WaterLiters  *float64 `json:"water_liters" validate:"omitempty,gte=0,lte=1000000"`
+	PlasticItems *int     `json:"plastic_items" validate:"omitempty,gte=0,lte=10000"`
+	DurationDays *int     `json:"duration_days" validate:"omitempty,gte=1,lte=365"`
+	Recurrence   *string  `json:"recurrence" validate:"omitempty,oneof=none daily weekly monthly"`
+	ProofType    *string  `json:"proof_type" validate:"omitempty,oneof=none photo note photo_and_note"`
+	TrackMode    *string  `json:"track_mode" validate:"omitempty,oneof=none walking cycling public_transport"`
+}
+
+type GetSubmissionsQuery struct {
+	Page        int    `query:"page" validate:"omitempty,gte=1"`
+	Limit       int    `query:"limit" validate:"omitempty,gte=1,lte=100"`
+	ChallengeID string `query:"challenge_id" validate:"omitempty,uuid"`
+}
+
+type ApproveSubmissionRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+type RejectSubmissionRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+type SubmissionResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	UserName       string     `json:"user_name"`
+	ChallengeID    uuid.UUID  `json:"challenge_id"`
+	ChallengeTitle string     `json:"challenge_title"`
+	ExpReward      int        `json:"exp_reward"`
+	PeriodKey      string     `json:"period_key"`
+	ProofImageURL  *string    `json:"proof_image_url"`
+	ProofNote      *string    `json:"proof_note"`
+	SubmittedAt    *time.Time `json:"submitted_at"`
+
+	Track *TrackSummaryResponse `json:"track,omitempty"`
+}
+
+type TrackSummaryResponse struct {
+	Format          string    `json:"format"`
+	Points          int       `json:"points"`
+	DistanceMeters  float64   `json:"distance_meters"`
+	DurationSeconds int       `json:"duration_seconds"`
+	AvgSpeedKmh     float64   `json:"avg_speed_kmh"`
+	MaxSpeedKmh     float64   `json:"max_speed_kmh"`
+	StartedAt       time.Time `json:"started_at"`
+	EndedAt         time.Time `json:"ended_at"`
+}
+
+type GetSubmissionsResponse struct {
+	Submissions []SubmissionResponse `json:"submissions"`
+	Page        int                  `json:"page"`
+	Limit       int                  `json:"limit"`
+	Total       int64                `json:"total"`
+}
+
 type GetChallengesResponse struct {
-	ID          uuid.UUID `json:"id"`
-	Title       string    `json:"title"`
-	Description *string   `json:"description"`
-	ExpReward   int       `json:"exp_reward"`
-	IsActive    bool      `json:"is_active"`
-	Status      *string   `json:"status,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID           uuid.UUID `json:"id"`
+	Title        string    `json:"title"`
+	Description  *string   `json:"description"`
+	ExpReward    int       `json:"exp_reward"`
+	Co2eKg       float64   `json:"co2e_kg"`
+	WaterLiters  float64   `json:"water_liters"`
+	PlasticItems int       `json:"plastic_items"`
+	DurationDays int       `json:"duration_days"`
+	Recurrence   string    `json:"recurrence"`
+	ProofType    string    `json:"proof_type"`
+	TrackMode    string    `json:"track_mode"`
+	IsActive     bool      `json:"is_active"`
+	Status       *string   `json:"status,omitempty"`
+
+	PeriodKey           string    `json:"period_key,omitempty"`
+	CompletedThisPeriod bool      `json:"completed_this_period"`
+	CreatedAt           time.Time `json:"created_at"`
 }
 
 type GetUserChallengesResponse struct {
+	ID          uuid.UUID  `json:"id"`
 	ChallengeID uuid.UUID  `json:"challenge_id"`
+	PeriodKey   string     `json:"period_key"`
 	Title       string     `json:"title"`
 	Description *string    `json:"description"`
 	ExpReward   int        `json:"exp_reward"`
 	Status      string     `json:"status"`
+	DueAt       *time.Time `json:"due_at"`
 	CompletedAt *time.Time `json:"completed_at"`
-	CreatedAt   time.Time  `json:"created_at"`
+	FailedAt    *time.Time `json:"failed_at,omitempty"`
+	AbandonedAt *time.Time `json:"abandoned_at,omitempty"`
+	SubmittedAt *time.Time `json:"submitted_at,omitempty"`
+
+	ProofImageURL *string    `json:"proof_image_url,omitempty"`
+	ProofNote     *string    `json:"proof_note,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewed_at,omitempty"`
+	ReviewReason  *string    `json:"review_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+
+	RemainingSeconds  *int64     `json:"remaining_seconds,omitempty"`
+	RetakeAvailableAt *time.Time `json:"retake_available_at,omitempty"`
+
+	Track *TrackSummaryResponse `json:"track,omitempty"`
+}
+
+type CreateBadgeRequest struct {
+	Type        string  `json:"type" validate:"required,max=50"`
+	Name        string  `json:"name" validate:"required,max=255"`
+	Description *string `json:"description"`
+	RequiredExp int     `json:"required_exp" validate:"gte=0"`
+}
+
+type UpdateBadgeRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
+	Description *string `json:"description"`
+	RequiredExp *int    `json:"required_exp" validate:"omitempty,gte=0"`
+}
+
+type BadgeResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Type        string    `json:"type"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description"`
+	ImageURL    *string   `json:"image_url"`
+	RequiredExp int       `json:"required_exp"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type GetBadgesResponse struct {
@@ -46,9 +186,17 @@ type GetBadgesResponse struct {
 }
 
 type GetUserStatsResponse struct {
-	CurrentExp      int                 `json:"current_exp"`
-	TotalChallenges int                 `json:"total_challenges"`
-	CompletedCount  int                 `json:"completed_challenges"`
-	OngoingCount    int                 `json:"ongoing_challenges"`
-	Badges          []GetBadgesResponse `json:"badges"`
+	CurrentExp      int                   `json:"current_exp"`
+	Level           int                   `json:"level"`
+	ExpIntoLevel    int                   `json:"exp_into_level"`
+	ExpToNextLevel  int                   `json:"exp_to_next_level"`
+	TotalChallenges int                   `json:"total_challenges"`
+	CompletedCount  int                   `json:"completed_challenges"`
+	OngoingCount    int                   `json:"ongoing_challenges"`
+	FailedCount     int                   `json:"failed_challenges"`
+	AbandonedCount  int                   `json:"abandoned_challenges"`
+	PendingCount    int                   `json:"pending_review_challenges"`
+	Impact          ImpactTotals          `json:"impact"`
+	ActivityTrend   ActivityTrendResponse `json:"activity_trend"`
+	Badges          []GetBadgesResponse   `json:"badges"`
 }
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TransportUsage struct {
+	Mode      string  `json:"mode" validate:"required,max=50"`
+	KmPerWeek float64 `json:"km_per_week" validate:"gte=0,lte=20000"`
+}
+
+type FootprintAssessmentRequest struct {
+	Region                 string           `json:"region" validate:"omitempty,max=10"`
+	Transport              []TransportUsage `json:"transport" validate:"max=20,dive"`
+	ElectricityKwhPerMonth float64          `json:"electricity_kwh_per_month" validate:"gte=0,lte=100000"`
+	Diet                   string           `json:"diet" validate:"required,max=50"`
+	WasteKgPerWeek         float64          `json:"waste_kg_per_week" validate:"gte=0,lte=1000"`
+	RecycledPercent        int              `json:"recycled_percent" validate:"gte=0,lte=100"`
+}
+
+type FootprintBreakdown struct {
+	TransportKg   float64 `json:"transport_kg"`
+	ElectricityKg float64 `json:"electricity_kg"`
+	DietKg        float64 `json:"diet_kg"`
+	WasteKg       float64 `json:"waste_kg"`
+}
+
+type FootprintAssessmentResponse struct {
+	ID            uuid.UUID          `json:"id"`
+	Region        string             `json:"region"`
+	FactorVersion int                `json:"factor_version"`
+	AnnualKg      float64            `json:"annual_kg"`
+	Breakdown     FootprintBreakdown `json:"breakdown"`
+	CreatedAt     time.Time          `json:"created_at"`
+}
+
+type EmissionFactorResponse struct {
+	Category string  `json:"category"`
+	Key      string  `json:"key"`
+	Region   string  `json:"region"`
+	Unit     string  `json:"unit"`
+	Factor   float64 `json:"factor"`
+	Source   *string `json:"source,omitempty"`
+}
+
+type GetEmissionFactorsResponse struct {
+	Version int                      `json:"version"`
+	Factors []EmissionFactorResponse `json:"factors"`
+}
+
+type GetAssessmentsQuery struct {
+	Page  int `query:"page" validate:"omitempty,gte=1"`
+	Limit int `query:"limit" validate:"omitempty,gte=1,lte=100"`
+}
+
+type GetAssessmentsResponse struct {
+	Assessments []FootprintAssessmentResponse `json:"assessments"`
+	Page        int                           `json:"page"`
+	Limit       int                           `json:"limit"`
+	Total       int64                         `json:"total"`
+}
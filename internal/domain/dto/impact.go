@@ -0,0 +1,20 @@
+package dto
+
+type ImpactTotals struct {
+	Co2eKg       float64 `json:"co2e_kg"`
+	WaterLiters  float64 `json:"water_liters"`
+	PlasticItems int     `json:"plastic_items"`
+}
+
+type ImpactBucket struct {
+	Period              string `json:"period"`
+	CompletedChallenges int    `json:"completed_challenges"`
+	ImpactTotals
+}
+
+type GetImpactResponse struct {
+	Total   ImpactTotals   `json:"total"`
+	Daily   []ImpactBucket `json:"daily"`
+	Weekly  []ImpactBucket `json:"weekly"`
+	Monthly []ImpactBucket `json:"monthly"`
+}
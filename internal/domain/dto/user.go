@@ -0,0 +1,12 @@
+package dto
+
+type ChangePasswordRequest struct {
+	CurrentPassword     string `json:"current_password"`
+	NewPassword         string `json:"new_password" validate:"required,min=8"`
+	LogoutOtherSessions bool   `json:"logout_other_sessions"`
+	RefreshToken        string `json:"refresh_token" validate:"required_if=LogoutOtherSessions true"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=user moderator admin org_admin"`
+}
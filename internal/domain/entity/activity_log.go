@@ -0,0 +1,41 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ActivityType string
+
+const (
+	ActivityTransport   ActivityType = "transport"
+	ActivityElectricity ActivityType = "electricity"
+)
+
+// ActivityLog is an everyday action logged by a user, converted to CO2e with
+// the emission factors that were current when it was last saved.
+type ActivityLog struct {
+	ID            uuid.UUID    `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID        uuid.UUID    `gorm:"column:user_id;type:char(36);not null;index:idx_activity_user_date"`
+	Type          ActivityType `gorm:"column:type;type:varchar(20);not null"`
+	Mode          *string      `gorm:"column:mode;type:varchar(50)"`
+	Quantity      float64      `gorm:"column:quantity;type:numeric(10,2);not null"`
+	Unit          string       `gorm:"column:unit;type:varchar(20);not null"`
+	Co2eKg        float64      `gorm:"column:co2e_kg;type:numeric(10,3);not null;default:0"`
+	Region        string       `gorm:"column:region;type:varchar(10);not null"`
+	FactorVersion int          `gorm:"column:factor_version;type:int;not null"`
+	OccurredOn    time.Time    `gorm:"column:occurred_on;type:date;not null;index:idx_activity_user_date"`
+	Note          *string      `gorm:"column:note;type:text"`
+	CreatedAt     *time.Time   `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+	UpdatedAt     *time.Time   `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+
+	User *User `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+}
+
+func (a *ActivityLog) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	a.ID = id
+	return
+}
@@ -7,14 +7,49 @@ import (
 	"gorm.io/gorm"
 )
 
+type ChallengeRecurrence string
+
+const (
+	RecurrenceNone    ChallengeRecurrence = "none"
+	RecurrenceDaily   ChallengeRecurrence = "daily"
+	RecurrenceWeekly  ChallengeRecurrence = "weekly"
+	RecurrenceMonthly ChallengeRecurrence = "monthly"
+)
+
+type ChallengeProofType string
+
+const (
+	ProofNone         ChallengeProofType = "none"
+	ProofPhoto        ChallengeProofType = "photo"
+	ProofNote         ChallengeProofType = "note"
+	ProofPhotoAndNote ChallengeProofType = "photo_and_note"
+)
+
+type ChallengeTrackMode string
+
+const (
+	TrackNone            ChallengeTrackMode = "none"
+	TrackWalking         ChallengeTrackMode = "walking"
+	TrackCycling         ChallengeTrackMode = "cycling"
+	TrackPublicTransport ChallengeTrackMode = "public_transport"
+)
+
 type Challenge struct {
-	ID          uuid.UUID  `gorm:"column:id;type:char(36);primaryKey;not null"`
-	Title       string     `gorm:"column:title;type:varchar(255);not null"`
-	Description *string    `gorm:"column:description;type:text"`
-	ExpReward   int        `gorm:"column:exp_reward;type:int;default:0"`
-	IsActive    bool       `gorm:"column:is_active;type:bool;default:true"`
-	CreatedAt   *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
-	UpdatedAt   *time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+	ID           uuid.UUID           `gorm:"column:id;type:char(36);primaryKey;not null"`
+	Title        string              `gorm:"column:title;type:varchar(255);not null"`
+	Description  *string             `gorm:"column:description;type:text"`
+	ExpReward    int                 `gorm:"column:exp_reward;type:int;default:0"`
+	Co2eKg       float64             `gorm:"column:co2e_kg;type:numeric(10,3);not null;default:0"`
+	WaterLiters  float64             `gorm:"column:water_liters;type:numeric(10,2);not null;default:0"`
+	PlasticItems int                 `gorm:"column:plastic_items;type:int;not null;default:0"`
+	DurationDays int                 `gorm:"column:duration_days;type:int;not null;default:1"`
+	Recurrence   ChallengeRecurrence `gorm:"column:recurrence;type:varchar(20);not null;default:'none'"`
+	ProofType    ChallengeProofType  `gorm:"column:proof_type;type:varchar(20);not null;default:'none'"`
+	TrackMode    ChallengeTrackMode  `gorm:"column:track_mode;type:varchar(20);not null;default:'none'"`
+	IsActive     bool                `gorm:"column:is_active;type:bool;default:true"`
+	CreatedAt    *time.Time          `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+	UpdatedAt    *time.Time          `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt      `gorm:"column:deleted_at;type:timestamp;index"`
 }
 
 func (c *Challenge) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,44 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmissionCategory string
+
+const (
+	EmissionTransport   EmissionCategory = "transport"
+	EmissionElectricity EmissionCategory = "electricity"
+	EmissionDiet        EmissionCategory = "diet"
+	EmissionWaste       EmissionCategory = "waste"
+)
+
+// GlobalRegion marks factors that apply wherever no region-specific factor
+// exists.
+const GlobalRegion = "GLOBAL"
+
+// EmissionFactor is the kg CO2e emitted per Unit of an activity. A new version
+// only needs to carry the factors it changes; each factor resolves to its
+// newest version, so an assessment can be traced back to the set it was
+// calculated with through the highest version it used.
+type EmissionFactor struct {
+	ID       uuid.UUID        `gorm:"column:id;type:char(36);primaryKey;not null"`
+	Version  int              `gorm:"column:version;type:int;not null;uniqueIndex:idx_emission_factor"`
+	Category EmissionCategory `gorm:"column:category;type:varchar(20);not null;uniqueIndex:idx_emission_factor"`
+	Key      string           `gorm:"column:key;type:varchar(50);not null;uniqueIndex:idx_emission_factor"`
+	Region   string           `gorm:"column:region;type:varchar(10);not null;default:'GLOBAL';uniqueIndex:idx_emission_factor"`
+	Unit     string           `gorm:"column:unit;type:varchar(20);not null"`
+	Factor   float64          `gorm:"column:factor;type:numeric(12,4);not null"`
+	Source   *string          `gorm:"column:source;type:text"`
+
+	CreatedAt *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+}
+
+func (e *EmissionFactor) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	e.ID = id
+	return
+}
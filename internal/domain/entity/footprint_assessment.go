@@ -0,0 +1,34 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// FootprintAssessment is one annual footprint estimate, stored with the
+// answers and factor version it was calculated from.
+type FootprintAssessment struct {
+	ID            uuid.UUID `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID        uuid.UUID `gorm:"column:user_id;type:char(36);not null;index"`
+	Region        string    `gorm:"column:region;type:varchar(10);not null"`
+	FactorVersion int       `gorm:"column:factor_version;type:int;not null"`
+	Answers       string    `gorm:"column:answers;type:jsonb;not null"`
+
+	TransportKg   float64 `gorm:"column:transport_kg;type:numeric(12,2);not null;default:0"`
+	ElectricityKg float64 `gorm:"column:electricity_kg;type:numeric(12,2);not null;default:0"`
+	DietKg        float64 `gorm:"column:diet_kg;type:numeric(12,2);not null;default:0"`
+	WasteKg       float64 `gorm:"column:waste_kg;type:numeric(12,2);not null;default:0"`
+	TotalKg       float64 `gorm:"column:total_kg;type:numeric(12,2);not null;default:0"`
+
+	CreatedAt *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+
+	User *User `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+}
+
+func (f *FootprintAssessment) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	f.ID = id
+	return
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ImpactRecord is the impact credited to a user for one completed challenge
+// attempt, kept so totals can be broken down over time.
+type ImpactRecord struct {
+	ID              uuid.UUID  `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID          uuid.UUID  `gorm:"column:user_id;type:char(36);not null;index:idx_impact_user_recorded"`
+	UserChallengeID uuid.UUID  `gorm:"column:user_challenge_id;type:char(36);not null;uniqueIndex"`
+	ChallengeID     uuid.UUID  `gorm:"column:challenge_id;type:char(36);not null"`
+	Co2eKg          float64    `gorm:"column:co2e_kg;type:numeric(10,3);not null;default:0"`
+	WaterLiters     float64    `gorm:"column:water_liters;type:numeric(10,2);not null;default:0"`
+	PlasticItems    int        `gorm:"column:plastic_items;type:int;not null;default:0"`
+	RecordedAt      time.Time  `gorm:"column:recorded_at;type:timestamp;not null;index:idx_impact_user_recorded"`
+	CreatedAt       *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+
+	User          *User          `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	UserChallenge *UserChallenge `gorm:"foreignKey:user_challenge_id;constraint:OnDelete:CASCADE"`
+}
+
+func (i *ImpactRecord) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	i.ID = id
+	return
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MFARecoveryCode struct {
+	ID        uuid.UUID  `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID    uuid.UUID  `gorm:"column:user_id;type:char(36);index;not null"`
+	CodeHash  string     `gorm:"column:code_hash;type:varchar(64);not null"`
+	UsedAt    *time.Time `gorm:"column:used_at;type:timestamp"`
+	CreatedAt *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+
+	User *User `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+}
+
+func (m *MFARecoveryCode) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	m.ID = id
+	return
+}
@@ -0,0 +1,33 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ModerationDecisionType string
+
+const (
+	DecisionApproved ModerationDecisionType = "approved"
+	DecisionRejected ModerationDecisionType = "rejected"
+)
+
+type ModerationDecision struct {
+	ID              uuid.UUID              `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserChallengeID uuid.UUID              `gorm:"column:user_challenge_id;type:char(36);index;not null"`
+	ModeratorID     uuid.UUID              `gorm:"column:moderator_id;type:char(36);index;not null"`
+	Decision        ModerationDecisionType `gorm:"column:decision;type:varchar(20);not null"`
+	Reason          *string                `gorm:"column:reason;type:text"`
+	CreatedAt       *time.Time             `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+
+	UserChallenge *UserChallenge `gorm:"foreignKey:user_challenge_id;constraint:OnDelete:CASCADE"`
+	Moderator     *User          `gorm:"foreignKey:moderator_id;constraint:OnDelete:CASCADE"`
+}
+
+func (m *ModerationDecision) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	m.ID = id
+	return
+}
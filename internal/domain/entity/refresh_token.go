@@ -8,12 +8,18 @@ import (
 )
 
 type RefreshToken struct {
-	ID        uuid.UUID  `gorm:"column:id;type:char(36);primaryKey;not null"`
-	UserID    uuid.UUID  `gorm:"column:user_id;type:char(36);not null"`
-	User      *User      `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
-	Token     string     `gorm:"column:token;type:varchar(255);not null"`
-	CreatedAt *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
-	UpdatedAt *time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+	ID         uuid.UUID  `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID     uuid.UUID  `gorm:"column:user_id;type:char(36);not null"`
+	User       *User      `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	FamilyID   uuid.UUID  `gorm:"column:family_id;type:char(36);index"`
+	Token      string     `gorm:"column:token;type:varchar(255);index;not null"`
+	DeviceName *string    `gorm:"column:device_name;type:varchar(255)"`
+	UserAgent  *string    `gorm:"column:user_agent;type:text"`
+	IPAddress  *string    `gorm:"column:ip_address;type:varchar(45)"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at;type:timestamp"`
+	ConsumedAt *time.Time `gorm:"column:consumed_at;type:timestamp"`
+	CreatedAt  *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
 }
 
 func (r *RefreshToken) BeforeCreate(tx *gorm.DB) (err error) {
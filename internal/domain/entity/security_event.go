@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SecurityEventType string
+
+const (
+	SecurityEventRefreshTokenReuse SecurityEventType = "refresh_token_reuse"
+)
+
+type SecurityEvent struct {
+	ID        uuid.UUID         `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID    uuid.UUID         `gorm:"column:user_id;type:char(36);index;not null"`
+	Type      SecurityEventType `gorm:"column:type;type:varchar(50);not null"`
+	IPAddress *string           `gorm:"column:ip_address;type:varchar(45)"`
+	UserAgent *string           `gorm:"column:user_agent;type:text"`
+	Details   *string           `gorm:"column:details;type:text"`
+	CreatedAt *time.Time        `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+
+	User *User `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+}
+
+func (s *SecurityEvent) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	s.ID = id
+	return
+}
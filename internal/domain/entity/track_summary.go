@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TrackSummary is what was parsed from a GPX or FIT file uploaded as proof
+// for a track-verified challenge. The file itself is not kept; its hash
+// prevents the same recording from being reused for another attempt.
+type TrackSummary struct {
+	ID              uuid.UUID `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserChallengeID uuid.UUID `gorm:"column:user_challenge_id;type:char(36);not null;uniqueIndex"`
+	UserID          uuid.UUID `gorm:"column:user_id;type:char(36);not null;uniqueIndex:idx_track_user_file"`
+	FileHash        string    `gorm:"column:file_hash;type:char(64);not null;uniqueIndex:idx_track_user_file"`
+	Format          string    `gorm:"column:format;type:varchar(10);not null"`
+	Points          int       `gorm:"column:points;type:int;not null"`
+	DistanceMeters  float64   `gorm:"column:distance_meters;type:numeric(12,2);not null"`
+	DurationSeconds int       `gorm:"column:duration_seconds;type:int;not null"`
+	AvgSpeedKmh     float64   `gorm:"column:avg_speed_kmh;type:numeric(8,2);not null"`
+	MaxSpeedKmh     float64   `gorm:"column:max_speed_kmh;type:numeric(8,2);not null"`
+	StartedAt       time.Time `gorm:"column:started_at;type:timestamp;not null"`
+	EndedAt         time.Time `gorm:"column:ended_at;type:timestamp;not null"`
+
+	CreatedAt *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+
+	User          *User          `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	UserChallenge *UserChallenge `gorm:"foreignKey:user_challenge_id;constraint:OnDelete:CASCADE"`
+}
+
+func (t *TrackSummary) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	t.ID = id
+	return
+}
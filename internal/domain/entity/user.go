@@ -7,6 +7,15 @@ import (
 	"gorm.io/gorm"
 )
 
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+	RoleOrgAdmin  Role = "org_admin"
+)
+
 type User struct {
 	ID           uuid.UUID      `gorm:"column:id;type:char(36);primaryKey;not null"`
 	Email        string         `gorm:"column:email;type:varchar(255);unique;not null"`
@@ -14,10 +23,18 @@ type User struct {
 	Name         string         `gorm:"column:name;type:varchar(255);not null"`
 	GoogleID     *string        `gorm:"column:google_id;type:varchar(255);unique"`
 	Verified     bool           `gorm:"column:verified;type:bool;default:false"`
-	Exp int `gorm:"column:exp;type:int;default:0"`
+	Role         Role           `gorm:"column:role;type:varchar(20);not null;default:user"`
+	TOTPSecret   *string        `gorm:"column:totp_secret;type:varchar(255)"`
+	TOTPLastStep int64          `gorm:"column:totp_last_step;type:bigint;not null;default:0"`
+	MFAEnabled   bool           `gorm:"column:mfa_enabled;type:bool;default:false"`
+	Exp          int            `gorm:"column:exp;type:int;default:0"`
 	RefreshToken []RefreshToken `gorm:"foreignKey:user_id;constraint:OnUpdate:SET NULL,OnDelete:CASCADE;"`
 	CreatedAt    *time.Time     `gorm:"column:created_at;type:timestamp;autoCreateTime"`
 	UpdatedAt    *time.Time     `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+
+	TotalCo2eKg       float64 `gorm:"column:total_co2e_kg;type:numeric(12,3);not null;default:0"`
+	TotalWaterLiters  float64 `gorm:"column:total_water_liters;type:numeric(12,2);not null;default:0"`
+	TotalPlasticItems int     `gorm:"column:total_plastic_items;type:int;not null;default:0"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
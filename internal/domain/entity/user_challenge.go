@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type ChallengeStatus string
@@ -12,16 +13,39 @@ const (
 	StatusOngoing   ChallengeStatus = "ongoing"
 	StatusCompleted ChallengeStatus = "completed"
 	StatusFailed    ChallengeStatus = "failed"
+	StatusAbandoned ChallengeStatus = "abandoned"
+
+	StatusPendingReview ChallengeStatus = "pending_review"
+	StatusRejected      ChallengeStatus = "rejected"
 )
 
 type UserChallenge struct {
-	UserID      uuid.UUID       `gorm:"column:user_id;type:char(36);primaryKey;not null"`
-	ChallengeID uuid.UUID       `gorm:"column:challenge_id;type:char(36);primaryKey;not null"`
+	ID          uuid.UUID       `gorm:"column:id;type:char(36);primaryKey;not null"`
+	UserID      uuid.UUID       `gorm:"column:user_id;type:char(36);not null;uniqueIndex:idx_user_challenge_period"`
+	ChallengeID uuid.UUID       `gorm:"column:challenge_id;type:char(36);not null;uniqueIndex:idx_user_challenge_period"`
+	PeriodKey   string          `gorm:"column:period_key;type:varchar(20);not null;default:'once';uniqueIndex:idx_user_challenge_period"`
 	Status      ChallengeStatus `gorm:"column:status;type:varchar(20);default:'ongoing'"`
+	StartedAt   *time.Time      `gorm:"column:started_at;type:timestamp"`
+	DueAt       *time.Time      `gorm:"column:due_at;type:timestamp;index"`
 	CompletedAt *time.Time      `gorm:"column:completed_at;type:timestamp"`
-	CreatedAt   *time.Time      `gorm:"column:created_at;type:timestamp;autoCreateTime"`
-	UpdatedAt   *time.Time      `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+	FailedAt    *time.Time      `gorm:"column:failed_at;type:timestamp"`
+	AbandonedAt *time.Time      `gorm:"column:abandoned_at;type:timestamp"`
+
+	ProofImageURL *string    `gorm:"column:proof_image_url;type:text"`
+	ProofNote     *string    `gorm:"column:proof_note;type:text"`
+	SubmittedAt   *time.Time `gorm:"column:submitted_at;type:timestamp;index"`
+	ReviewedAt    *time.Time `gorm:"column:reviewed_at;type:timestamp"`
+	ReviewReason  *string    `gorm:"column:review_reason;type:text"`
+	CreatedAt     *time.Time `gorm:"column:created_at;type:timestamp;autoCreateTime"`
+	UpdatedAt     *time.Time `gorm:"column:updated_at;type:timestamp;autoUpdateTime"`
+
+	User      *User         `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
+	Challenge *Challenge    `gorm:"foreignKey:challenge_id;constraint:OnDelete:CASCADE"`
+	Track     *TrackSummary `gorm:"foreignKey:user_challenge_id"`
+}
 
-	User      *User      `gorm:"foreignKey:user_id;constraint:OnDelete:CASCADE"`
-	Challenge *Challenge `gorm:"foreignKey:challenge_id;constraint:OnDelete:CASCADE"`
+func (uc *UserChallenge) BeforeCreate(tx *gorm.DB) (err error) {
+	id, _ := uuid.NewV7()
+	uc.ID = id
+	return
 }
@@ -7,6 +7,9 @@ import (
 
 type EmailItf interface {
 	SendOTPEmail(to, otp string) error
+	SendResetPasswordEmail(to, otp string) error
+	SendChangeEmailOTP(to, otp string) error
+	SendEmailChangeNotice(to, newEmail string) error
 }
 
 type Email struct {
@@ -31,3 +34,36 @@ func (e *Email) SendOTPEmail(to, otp string) error {
 	dialer := gomail.NewDialer("smtp.gmail.com", 587, e.sender, e.password)
 	return dialer.DialAndSend(mail)
 }
+
+func (e *Email) SendResetPasswordEmail(to, otp string) error {
+	mail := gomail.NewMessage()
+	mail.SetHeader("From", e.sender)
+	mail.SetHeader("To", to)
+	mail.SetHeader("Subject", "Reset Your Password")
+	mail.SetBody("text/plain", "Your password reset code is: "+otp+"\n\nIf you did not request a password reset, you can ignore this email.")
+
+	dialer := gomail.NewDialer("smtp.gmail.com", 587, e.sender, e.password)
+	return dialer.DialAndSend(mail)
+}
+
+func (e *Email) SendChangeEmailOTP(to, otp string) error {
+	mail := gomail.NewMessage()
+	mail.SetHeader("From", e.sender)
+	mail.SetHeader("To", to)
+	mail.SetHeader("Subject", "Confirm Your New Email")
+	mail.SetBody("text/plain", "Your email change confirmation code is: "+otp)
+
+	dialer := gomail.NewDialer("smtp.gmail.com", 587, e.sender, e.password)
+	return dialer.DialAndSend(mail)
+}
+
+func (e *Email) SendEmailChangeNotice(to, newEmail string) error {
+	mail := gomail.NewMessage()
+	mail.SetHeader("From", e.sender)
+	mail.SetHeader("To", to)
+	mail.SetHeader("Subject", "Email Change Requested")
+	mail.SetBody("text/plain", "A request was made to change the email on your account to "+newEmail+".\n\nIf this wasn't you, change your password immediately.")
+
+	dialer := gomail.NewDialer("smtp.gmail.com", 587, e.sender, e.password)
+	return dialer.DialAndSend(mail)
+}
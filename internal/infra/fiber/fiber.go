@@ -20,6 +20,14 @@ func New(cfg *config.Config) *fiber.App {
 		IdleTimeout: 5 * time.Second,
 		JSONEncoder: gojson.Marshal,
 		JSONDecoder: gojson.Unmarshal,
+
+		// Attempt limits are keyed on ctx.IP(), so behind a reverse proxy the
+		// client IP must come from the proxy header, but only when the proxy
+		// is trusted; otherwise any client could pick its own bucket.
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: cfg.ProxyHeader != "",
+		TrustedProxies:          cfg.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
 	app.Use(recover.New())
@@ -34,7 +42,7 @@ func New(cfg *config.Config) *fiber.App {
 
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: cfg.FEURL,
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
+		AllowMethods: "GET, POST, PUT, PATCH, DELETE, OPTIONS",
 		AllowHeaders: "Content-Type, Authorization",
 	}))
 
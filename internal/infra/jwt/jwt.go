@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"crypto"
 	"errors"
 	"time"
 
@@ -10,58 +11,140 @@ import (
 )
 
 type JWTItf interface {
-	GenerateAccessToken(userId uuid.UUID, name, email string) (string, error)
+	GenerateAccessToken(userId uuid.UUID, name, email, role string) (string, error)
 	GenerateRefershToken(userId uuid.UUID, rememberMe bool) (string, error)
-	VerifyAccessToken(token string) (uuid.UUID, string, string, error)
-	VerifyRefreshToken(token string) (uuid.UUID, error)
+	VerifyAccessToken(token string) (*AccessClaims, error)
+	VerifyRefreshToken(token string) (uuid.UUID, bool, error)
+	JWKS() JWKS
 }
 
 type JWT struct {
-	accessSecret  string
-	refreshSecret string
+	accessSecret            string
+	refreshSecret           string
+	accessExpiry            time.Duration
+	refreshExpiry           time.Duration
+	rememberMeRefreshExpiry time.Duration
+
+	signingMethod    jwt.SigningMethod
+	signingKey       crypto.Signer
+	signingKeyID     string
+	verificationKeys map[string]crypto.PublicKey
+	jwks             JWKS
 }
 
-func NewJWT(cfg *config.Config) JWTItf {
-	return &JWT{
-		accessSecret:  cfg.AccessSecret,
-		refreshSecret: cfg.RefreshSecret,
+func NewJWT(cfg *config.Config) (JWTItf, error) {
+	j := &JWT{
+		accessSecret:            cfg.AccessSecret,
+		refreshSecret:           cfg.RefreshSecret,
+		accessExpiry:            cfg.AccessTokenExpiry,
+		refreshExpiry:           cfg.RefreshTokenExpiry,
+		rememberMeRefreshExpiry: cfg.RememberMeRefreshTokenExpiry,
+		signingMethod:           jwt.SigningMethodHS256,
+		verificationKeys:        make(map[string]crypto.PublicKey),
+		jwks:                    JWKS{Keys: []JWK{}},
 	}
+
+	if cfg.JWTPrivateKeyFile == "" {
+		return j, nil
+	}
+
+	signingKey, err := loadPrivateKey(cfg.JWTPrivateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	signingMethod, err := signingMethodFor(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	j.signingMethod = signingMethod
+	j.signingKey = signingKey
+
+	j.signingKeyID, err = j.addVerificationKey(signingKey.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, path := range cfg.JWTPublicKeyFiles {
+		if path == "" {
+			continue
+		}
+
+		publicKey, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, err := j.addVerificationKey(publicKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return j, nil
 }
 
 type AccessClaims struct {
 	UserID uuid.UUID `json:"user_id"`
 	Name   string    `json:"name"`
 	Email  string    `json:"email"`
+	Role   string    `json:"role"`
 	jwt.RegisteredClaims
 }
 
+// MintedAt returns when the token was issued. The iat claim only carries whole
+// seconds, so the millisecond timestamp of the UUIDv7 jti is used when present.
+func (c *AccessClaims) MintedAt() time.Time {
+	if jti, err := uuid.Parse(c.ID); err == nil && jti.Version() == 7 {
+		sec, nsec := jti.Time().UnixTime()
+		return time.Unix(sec, nsec)
+	}
+
+	if c.IssuedAt == nil {
+		return time.Time{}
+	}
+
+	return c.IssuedAt.Time
+}
+
 type RefreshClaims struct {
 	UserID     uuid.UUID `json:"user_id"`
 	RememberMe bool      `json:"remember_me"`
 	jwt.RegisteredClaims
 }
 
-func (j *JWT) GenerateAccessToken(userId uuid.UUID, name, email string) (string, error) {
+func (j *JWT) GenerateAccessToken(userId uuid.UUID, name, email, role string) (string, error) {
+	jti, err := uuid.NewV7()
+	if err != nil {
+		return "", err
+	}
+
 	claims := AccessClaims{
 		UserID: userId,
 		Name:   name,
 		Email:  email,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(15 * time.Minute)),
+			ID:        jti.String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.accessExpiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.accessSecret))
+	if j.signingKey == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(j.accessSecret))
+	}
+
+	token := jwt.NewWithClaims(j.signingMethod, claims)
+	token.Header["kid"] = j.signingKeyID
+	return token.SignedString(j.signingKey)
 }
 
 func (j *JWT) GenerateRefershToken(userId uuid.UUID, rememberMe bool) (string, error) {
-	var ttl time.Duration
+	ttl := j.refreshExpiry
 	if rememberMe {
-		ttl = 30 * 24 * time.Hour
-	} else {
-		ttl = 7 * 24 * time.Hour
+		ttl = j.rememberMeRefreshExpiry
 	}
 
 	claims := RefreshClaims{
@@ -77,36 +160,75 @@ func (j *JWT) GenerateRefershToken(userId uuid.UUID, rememberMe bool) (string, e
 	return token.SignedString([]byte(j.refreshSecret))
 }
 
-func (j *JWT) VerifyAccessToken(tokenString string) (uuid.UUID, string, string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(j.accessSecret), nil
-	})
+func (j *JWT) VerifyAccessToken(tokenString string) (*AccessClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &AccessClaims{}, j.accessKey, jwt.WithValidMethods([]string{j.signingMethod.Alg()}))
 
 	if err != nil {
-		return uuid.Nil, "", "", err
+		return nil, err
 	}
 
 	claims, ok := token.Claims.(*AccessClaims)
 	if !ok || !token.Valid {
-		return uuid.Nil, "", "", errors.New("couldn't parse access token claims")
+		return nil, errors.New("couldn't parse access token claims")
 	}
 
-	return claims.UserID, claims.Name, claims.Email, nil
+	return claims, nil
 }
 
-func (j *JWT) VerifyRefreshToken(tokenString string) (uuid.UUID, error) {
+func (j *JWT) VerifyRefreshToken(tokenString string) (uuid.UUID, bool, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(j.refreshSecret), nil
 	})
 
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil, false, err
 	}
 
 	claims, ok := token.Claims.(*RefreshClaims)
 	if !ok || !token.Valid {
-		return uuid.Nil, errors.New("couldn't parse refresh token claims")
+		return uuid.Nil, false, errors.New("couldn't parse refresh token claims")
+	}
+
+	return claims.UserID, claims.RememberMe, nil
+}
+
+func (j *JWT) JWKS() JWKS {
+	return j.jwks
+}
+
+func (j *JWT) accessKey(token *jwt.Token) (interface{}, error) {
+	if j.signingKey == nil {
+		return []byte(j.accessSecret), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.verificationKeys[kid]
+	if !ok {
+		return nil, errors.New("unknown access token key id")
+	}
+
+	return key, nil
+}
+
+func (j *JWT) addVerificationKey(key crypto.PublicKey) (string, error) {
+	method, err := signingMethodFor(key)
+	if err != nil {
+		return "", err
+	}
+
+	if method != j.signingMethod {
+		return "", errors.New("verification keys must use the same algorithm as the signing key")
+	}
+
+	jwk, err := toJWK(key)
+	if err != nil {
+		return "", err
+	}
+
+	if _, exists := j.verificationKeys[jwk.Kid]; !exists {
+		j.verificationKeys[jwk.Kid] = key
+		j.jwks.Keys = append(j.jwks.Keys, jwk)
 	}
 
-	return claims.UserID, nil
+	return jwk.Kid, nil
 }
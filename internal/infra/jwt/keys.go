@@ -0,0 +1,158 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	jwt "github.com/golang-jwt/jwt/v5"
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+func loadPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type in %s", path)
+		}
+
+		return signer, nil
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, path)
+}
+
+func loadPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "RSA PRIVATE KEY", "PRIVATE KEY":
+		signer, err := loadPrivateKey(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return signer.Public(), nil
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q in %s", block.Type, path)
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+
+	return block, nil
+}
+
+func signingMethodFor(key crypto.PublicKey) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PublicKey:
+		return jwt.SigningMethodRS256, nil
+	case ed25519.PublicKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, errors.New("unsupported key type, expected RSA or Ed25519")
+}
+
+// toJWK converts a public key to its JWK form, using the RFC 7638 thumbprint
+// as the key ID so the same key always gets the same kid.
+func toJWK(key crypto.PublicKey) (JWK, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+
+	var (
+		jwk       JWK
+		canonical []byte
+		err       error
+	)
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		jwk = JWK{
+			Kty: "RSA",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   encode(k.N.Bytes()),
+			E:   encode(big.NewInt(int64(k.E)).Bytes()),
+		}
+		canonical, err = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N})
+	case ed25519.PublicKey:
+		jwk = JWK{
+			Kty: "OKP",
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   encode(k),
+		}
+		canonical, err = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X})
+	default:
+		return JWK{}, errors.New("unsupported key type, expected RSA or Ed25519")
+	}
+
+	if err != nil {
+		return JWK{}, err
+	}
+
+	sum := sha256.Sum256(canonical)
+	jwk.Use = "sig"
+	jwk.Kid = encode(sum[:])
+
+	return jwk, nil
+}
@@ -0,0 +1,109 @@
+package level
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/Ablebil/eco-sample/config"
+)
+
+const (
+	CurveFormula = "formula"
+	CurveTable   = "table"
+)
+
+var (
+	ErrUnknownCurve      = errors.New("unknown level curve")
+	ErrInvalidThresholds = errors.New("level thresholds must be positive and strictly increasing")
+	ErrInvalidFormula    = errors.New("level formula needs a positive base exp, a growth of at least 1 and a max level above 1")
+)
+
+type Progress struct {
+	Level          int
+	ExpIntoLevel   int
+	ExpToNextLevel int
+}
+
+type CurveItf interface {
+	Progress(exp int) Progress
+	Level(exp int) int
+}
+
+// Curve maps total EXP to a level. thresholds[i] is the total EXP needed to
+// reach level i+2; level 1 starts at 0 EXP.
+type Curve struct {
+	thresholds []int
+}
+
+// NewCurve builds the curve selected by LEVEL_CURVE. The formula curve needs
+// LEVEL_BASE_EXP to go from level 1 to 2, and each following level needs
+// LEVEL_GROWTH times more than the one before, up to LEVEL_MAX. The table
+// curve takes the cumulative thresholds from LEVEL_THRESHOLDS as is.
+func NewCurve(cfg *config.Config) (CurveItf, error) {
+	switch cfg.LevelCurve {
+	case CurveFormula:
+		thresholds, err := formulaThresholds(cfg.LevelBaseExp, cfg.LevelGrowth, cfg.LevelMax)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Curve{thresholds: thresholds}, nil
+	case CurveTable:
+		if len(cfg.LevelThresholds) == 0 {
+			return nil, ErrInvalidThresholds
+		}
+
+		for i, threshold := range cfg.LevelThresholds {
+			if threshold <= 0 || (i > 0 && threshold <= cfg.LevelThresholds[i-1]) {
+				return nil, ErrInvalidThresholds
+			}
+		}
+
+		return &Curve{thresholds: cfg.LevelThresholds}, nil
+	default:
+		return nil, ErrUnknownCurve
+	}
+}
+
+func formulaThresholds(baseExp int, growth float64, maxLevel int) ([]int, error) {
+	if baseExp <= 0 || growth < 1 || maxLevel < 2 {
+		return nil, ErrInvalidFormula
+	}
+
+	thresholds := make([]int, 0, maxLevel-1)
+	total := 0
+	step := float64(baseExp)
+	for level := 2; level <= maxLevel; level++ {
+		total += int(math.Round(step))
+		thresholds = append(thresholds, total)
+		step *= growth
+	}
+
+	return thresholds, nil
+}
+
+func (c *Curve) Level(exp int) int {
+	return sort.SearchInts(c.thresholds, exp+1) + 1
+}
+
+func (c *Curve) Progress(exp int) Progress {
+	level := c.Level(exp)
+
+	levelStart := 0
+	if level > 1 {
+		levelStart = c.thresholds[level-2]
+	}
+
+	progress := Progress{
+		Level:        level,
+		ExpIntoLevel: exp - levelStart,
+	}
+
+	// At the max level there is nothing left to earn towards.
+	if level-1 < len(c.thresholds) {
+		progress.ExpToNextLevel = c.thresholds[level-1] - exp
+	}
+
+	return progress
+}
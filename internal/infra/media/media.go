@@ -0,0 +1,166 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+var ErrUnsupportedImage = errors.New("unsupported or malformed image")
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// Metadata chunks and segments that can carry EXIF, XMP or IPTC data such as
+// GPS coordinates, camera serials or free text.
+var (
+	strippedJPEGMarkers = map[byte]bool{
+		0xE1: true, // APP1: EXIF and XMP
+		0xED: true, // APP13: IPTC
+		0xFE: true, // COM
+	}
+	strippedPNGChunks = map[string]bool{
+		"eXIf": true,
+		"tEXt": true,
+		"zTXt": true,
+		"iTXt": true,
+		"tIME": true,
+	}
+)
+
+// DetectImageType returns the MIME type of data when it is a JPEG or PNG.
+func DetectImageType(data []byte) (string, bool) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png":
+		return contentType, true
+	default:
+		return "", false
+	}
+}
+
+// StripMetadata removes metadata from a JPEG or PNG without re-encoding the
+// pixels and checks that the result still decodes as an image.
+func StripMetadata(data []byte) ([]byte, error) {
+	contentType, ok := DetectImageType(data)
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	var stripped []byte
+	var err error
+	if contentType == "image/png" {
+		stripped, err = stripPNG(data)
+	} else {
+		stripped, err = stripJPEG(data)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := image.DecodeConfig(bytes.NewReader(stripped)); err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	return stripped, nil
+}
+
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, ErrUnsupportedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+
+	i := 2
+	for i < len(data) {
+		if data[i] != 0xFF {
+			return nil, ErrUnsupportedImage
+		}
+
+		// Markers may be preceded by any number of 0xFF fill bytes.
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+
+		if i >= len(data) {
+			return nil, ErrUnsupportedImage
+		}
+
+		marker := data[i]
+		i++
+
+		if marker == 0xD9 {
+			return append(out, 0xFF, 0xD9), nil
+		}
+
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, 0xFF, marker)
+			continue
+		}
+
+		if i+2 > len(data) {
+			return nil, ErrUnsupportedImage
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, ErrUnsupportedImage
+		}
+
+		segment := data[i : i+length]
+		i += length
+
+		// Entropy-coded data follows the start of scan header; everything
+		// after it is image data and trailing markers, copied verbatim.
+		if marker == 0xDA {
+			out = append(out, 0xFF, marker)
+			out = append(out, segment...)
+			return append(out, data[i:]...), nil
+		}
+
+		if strippedJPEGMarkers[marker] {
+			continue
+		}
+
+		out = append(out, 0xFF, marker)
+		out = append(out, segment...)
+	}
+
+	return nil, ErrUnsupportedImage
+}
+
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrUnsupportedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	i := len(pngSignature)
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if length < 0 || end > len(data) {
+			return nil, ErrUnsupportedImage
+		}
+
+		chunkType := string(data[i+4 : i+8])
+		if !strippedPNGChunks[chunkType] {
+			out = append(out, data[i:end]...)
+		}
+
+		i = end
+		if chunkType == "IEND" {
+			return out, nil
+		}
+	}
+
+	return nil, ErrUnsupportedImage
+}
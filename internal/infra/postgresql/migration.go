@@ -6,12 +6,77 @@ import (
 )
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := migrateUserChallengeKey(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
 		&entity.User{},
 		&entity.RefreshToken{},
 		&entity.Challenge{},
 		&entity.UserChallenge{},
 		&entity.Badge{},
 		&entity.UserBadge{},
-	)
+		&entity.SecurityEvent{},
+		&entity.MFARecoveryCode{},
+		&entity.ModerationDecision{},
+		&entity.ImpactRecord{},
+		&entity.EmissionFactor{},
+		&entity.FootprintAssessment{},
+		&entity.ActivityLog{},
+		&entity.TrackSummary{},
+	); err != nil {
+		return err
+	}
+
+	return hashRefreshTokens(db)
+}
+
+// migrateUserChallengeKey replaces the old (user_id, challenge_id) primary key
+// of user_challenges with a surrogate id, which AutoMigrate cannot do itself.
+// Existing rows become the "once" period of their challenge.
+func migrateUserChallengeKey(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&entity.UserChallenge{}) || migrator.HasColumn(&entity.UserChallenge{}, "id") {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`ALTER TABLE user_challenges ADD COLUMN id char(36)`,
+			`UPDATE user_challenges SET id = gen_random_uuid()::text`,
+			`ALTER TABLE user_challenges ALTER COLUMN id SET NOT NULL`,
+			`ALTER TABLE user_challenges DROP CONSTRAINT IF EXISTS user_challenges_pkey`,
+			`ALTER TABLE user_challenges ADD PRIMARY KEY (id)`,
+			`ALTER TABLE user_challenges ADD COLUMN IF NOT EXISTS period_key varchar(20) NOT NULL DEFAULT 'once'`,
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// hashRefreshTokens upgrades refresh tokens stored before tokens were hashed
+// and grouped into families. Legacy rows still hold the raw JWT, which always
+// contains a dot, so existing sessions keep working after the upgrade.
+func hashRefreshTokens(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`UPDATE refresh_tokens SET token = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token LIKE '%.%'`,
+			`UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL`,
+		}
+
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
@@ -45,6 +45,7 @@ func CheckError(err error, target any) bool {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == string(t) {
 			log.Printf("PostgreSQL Error [%s]: %s\n", pgErr.Code, pgErr.Message)
+			return true
 		}
 	case error:
 		if errors.Is(err, t) {
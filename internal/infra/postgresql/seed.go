@@ -20,6 +20,10 @@ func Seed(db *gorm.DB) error {
 		return err
 	}
 
+	if err := seedEmissionFactors(db); err != nil {
+		return err
+	}
+
 	log.Println("Database seeding completed successfully")
 	return nil
 }
@@ -90,70 +94,102 @@ func seedChallenges(db *gorm.DB) error {
 
 	challenges := []entity.Challenge{
 		{
-			Title:       "Meatless Monday",
-			Description: stringPtr("Go vegetarian for a full day. Skip meat and try delicious plant-based alternatives!"),
-			ExpReward:   25,
-			IsActive:    true,
+			Title:        "Meatless Monday",
+			Description:  stringPtr("Go vegetarian for a full day. Skip meat and try delicious plant-based alternatives!"),
+			ExpReward:    25,
+			Co2eKg:       3.2,
+			WaterLiters:  1500,
+			DurationDays: 1,
+			Recurrence:   entity.RecurrenceWeekly,
+			IsActive:     true,
 		},
 		{
-			Title:       "Bike to Work",
-			Description: stringPtr("Cycle to work instead of using motorized transport. Great for health and environment!"),
-			ExpReward:   30,
-			IsActive:    true,
+			Title:        "Bike to Work",
+			Description:  stringPtr("Cycle to work instead of using motorized transport. Great for health and environment!"),
+			ExpReward:    30,
+			Co2eKg:       2.3,
+			DurationDays: 1,
+			Recurrence:   entity.RecurrenceDaily,
+			TrackMode:    entity.TrackCycling,
+			IsActive:     true,
 		},
 		{
-			Title:       "Zero Plastic Day",
-			Description: stringPtr("Avoid single-use plastics for an entire day. Bring your own bags and containers!"),
-			ExpReward:   35,
-			IsActive:    true,
+			Title:        "Zero Plastic Day",
+			Description:  stringPtr("Avoid single-use plastics for an entire day. Bring your own bags and containers!"),
+			ExpReward:    35,
+			Co2eKg:       0.3,
+			PlasticItems: 5,
+			DurationDays: 1,
+			IsActive:     true,
 		},
 		{
-			Title:       "Energy Saver",
-			Description: stringPtr("Reduce electricity usage by 20% for a day. Unplug devices and use natural light!"),
-			ExpReward:   20,
-			IsActive:    true,
+			Title:        "Energy Saver",
+			Description:  stringPtr("Reduce electricity usage by 20% for a day. Unplug devices and use natural light!"),
+			ExpReward:    20,
+			Co2eKg:       1.0,
+			DurationDays: 1,
+			Recurrence:   entity.RecurrenceDaily,
+			IsActive:     true,
 		},
 		{
-			Title:       "Water Conservation",
-			Description: stringPtr("Implement water-saving techniques for a week. Take shorter showers and fix leaks!"),
-			ExpReward:   40,
-			IsActive:    true,
+			Title:        "Water Conservation",
+			Description:  stringPtr("Implement water-saving techniques for a week. Take shorter showers and fix leaks!"),
+			ExpReward:    40,
+			Co2eKg:       0.4,
+			WaterLiters:  700,
+			DurationDays: 7,
+			IsActive:     true,
 		},
 		{
-			Title:       "Public Transport Champion",
-			Description: stringPtr("Use public transportation for all your trips in a day instead of private vehicles."),
-			ExpReward:   25,
-			IsActive:    true,
+			Title:        "Public Transport Champion",
+			Description:  stringPtr("Use public transportation for all your trips in a day instead of private vehicles."),
+			ExpReward:    25,
+			Co2eKg:       1.8,
+			DurationDays: 1,
+			Recurrence:   entity.RecurrenceDaily,
+			TrackMode:    entity.TrackPublicTransport,
+			IsActive:     true,
 		},
 		{
-			Title:       "Digital Minimalist",
-			Description: stringPtr("Reduce screen time and digital consumption for a day. Enjoy offline activities!"),
-			ExpReward:   15,
-			IsActive:    true,
+			Title:        "Digital Minimalist",
+			Description:  stringPtr("Reduce screen time and digital consumption for a day. Enjoy offline activities!"),
+			ExpReward:    15,
+			Co2eKg:       0.1,
+			DurationDays: 1,
+			IsActive:     true,
 		},
 		{
-			Title:       "Local Food Hero",
-			Description: stringPtr("Buy only locally sourced food for a week. Support local farmers and reduce transport emissions!"),
-			ExpReward:   45,
-			IsActive:    true,
+			Title:        "Local Food Hero",
+			Description:  stringPtr("Buy only locally sourced food for a week. Support local farmers and reduce transport emissions!"),
+			ExpReward:    45,
+			Co2eKg:       3.0,
+			DurationDays: 7,
+			IsActive:     true,
 		},
 		{
-			Title:       "Reusable Bottle Week",
-			Description: stringPtr("Use only reusable water bottles for a full week. Help reduce plastic waste!"),
-			ExpReward:   30,
-			IsActive:    true,
+			Title:        "Reusable Bottle Week",
+			Description:  stringPtr("Use only reusable water bottles for a full week. Help reduce plastic waste!"),
+			ExpReward:    30,
+			Co2eKg:       0.6,
+			PlasticItems: 14,
+			DurationDays: 7,
+			IsActive:     true,
 		},
 		{
-			Title:       "Paperless Day",
-			Description: stringPtr("Go completely paperless for a day. Use digital alternatives for all documents!"),
-			ExpReward:   20,
-			IsActive:    true,
+			Title:        "Paperless Day",
+			Description:  stringPtr("Go completely paperless for a day. Use digital alternatives for all documents!"),
+			ExpReward:    20,
+			Co2eKg:       0.2,
+			WaterLiters:  10,
+			DurationDays: 1,
+			Recurrence:   entity.RecurrenceDaily,
+			IsActive:     true,
 		},
 	}
 
 	for _, challenge := range challenges {
 		var existingChallenge entity.Challenge
-		err := db.Where("title = ?", challenge.Title).First(&existingChallenge).Error
+		err := db.Unscoped().Where("title = ?", challenge.Title).First(&existingChallenge).Error
 
 		if err == gorm.ErrRecordNotFound {
 			id, _ := uuid.NewV7()
@@ -179,6 +215,77 @@ func seedChallenges(db *gorm.DB) error {
 	return nil
 }
 
+func seedEmissionFactors(db *gorm.DB) error {
+	log.Println("Seeding emission factors...")
+
+	const version = 1
+	transportSource := stringPtr("UK DESNZ greenhouse gas conversion factors 2023, per passenger-km")
+	dietSource := stringPtr("Scarborough et al. 2014, dietary greenhouse gas emissions, annualised")
+	wasteSource := stringPtr("US EPA WARM v15, mixed municipal solid waste")
+
+	factors := []entity.EmissionFactor{
+		{Category: entity.EmissionTransport, Key: "car_petrol", Unit: "km", Factor: 0.1645, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "car_diesel", Unit: "km", Factor: 0.1680, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "car_electric", Unit: "km", Factor: 0.0470, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "motorcycle", Unit: "km", Factor: 0.1140, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "bus", Unit: "km", Factor: 0.1020, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "train", Unit: "km", Factor: 0.0355, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "domestic_flight", Unit: "km", Factor: 0.2460, Source: transportSource},
+		{Category: entity.EmissionTransport, Key: "bicycle", Unit: "km", Factor: 0},
+		{Category: entity.EmissionTransport, Key: "walking", Unit: "km", Factor: 0},
+		{Category: entity.EmissionElectricity, Key: "grid", Region: "ID", Unit: "kWh", Factor: 0.8700, Source: stringPtr("Indonesia Ministry of Energy and Mineral Resources, national grid emission factor")},
+		{Category: entity.EmissionDiet, Key: "heavy_meat", Unit: "year", Factor: 2625, Source: dietSource},
+		{Category: entity.EmissionDiet, Key: "omnivore", Unit: "year", Factor: 2055, Source: dietSource},
+		{Category: entity.EmissionDiet, Key: "pescatarian", Unit: "year", Factor: 1425, Source: dietSource},
+		{Category: entity.EmissionDiet, Key: "vegetarian", Unit: "year", Factor: 1390, Source: dietSource},
+		{Category: entity.EmissionDiet, Key: "vegan", Unit: "year", Factor: 1055, Source: dietSource},
+		{Category: entity.EmissionWaste, Key: "landfill", Unit: "kg", Factor: 0.5800, Source: wasteSource},
+		{Category: entity.EmissionWaste, Key: "recycled", Unit: "kg", Factor: 0.0210, Source: wasteSource},
+	}
+
+	for _, factor := range factors {
+		factor.Version = version
+		if factor.Region == "" {
+			factor.Region = entity.GlobalRegion
+		}
+
+		var existingFactor entity.EmissionFactor
+		err := db.Where("version = ? AND category = ? AND key = ? AND region = ?", factor.Version, factor.Category, factor.Key, factor.Region).
+			First(&existingFactor).Error
+
+		if err == gorm.ErrRecordNotFound {
+			if err := db.Create(&factor).Error; err != nil {
+				log.Printf("Error creating emission factor %s/%s: %v", factor.Category, factor.Key, err)
+				return err
+			}
+			log.Printf("Created emission factor: %s/%s (%s)", factor.Category, factor.Key, factor.Region)
+		} else if err != nil {
+			log.Printf("Error checking emission factor %s/%s: %v", factor.Category, factor.Key, err)
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SeedAdmins promotes already registered accounts to admin, which is the only
+// way to get the first admin since role changes require one.
+func SeedAdmins(db *gorm.DB, emails []string) error {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	result := db.Model(&entity.User{}).
+		Where("email IN ? AND role <> ?", emails, entity.RoleAdmin).
+		Update("role", entity.RoleAdmin)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	log.Printf("Promoted %d user(s) to admin", result.RowsAffected)
+	return nil
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -1,19 +1,47 @@
 package redis
 
 import (
+	"context"
+	"strconv"
 	"time"
 
 	"github.com/Ablebil/eco-sample/config"
 	"github.com/gofiber/storage/redis"
 )
 
+type OTPPurpose string
+
+const (
+	OTPPurposeVerifyEmail   OTPPurpose = "verify_email"
+	OTPPurposeResetPassword OTPPurpose = "reset_password"
+	OTPPurposeChangeEmail   OTPPurpose = "change_email"
+)
+
 type RedisItf interface {
-	SetOTP(email string, otp string, exp time.Duration) error
-	GetOTP(email string) (string, error)
-	DeleteOTP(email string) error
+	SetOTP(purpose OTPPurpose, email string, otp string, exp time.Duration) error
+	GetOTP(purpose OTPPurpose, email string) (string, error)
+	DeleteOTP(purpose OTPPurpose, email string) error
+	SetOTPCooldown(purpose OTPPurpose, email string, exp time.Duration) error
+	GetOTPCooldownTTL(purpose OTPPurpose, email string) (time.Duration, error)
+	IncrementOTPDailyCount(email string) (int64, error)
+	SetPendingEmail(userID string, email string, exp time.Duration) error
+	GetPendingEmail(userID string) (string, error)
+	DeletePendingEmail(userID string) error
 	SetOAuthState(state string, value []byte, exp time.Duration) error
 	GetOAuthState(state string) ([]byte, error)
 	DeleteOAuthState(state string) error
+	SetMFAChallenge(token string, value []byte, exp time.Duration) error
+	GetMFAChallenge(token string) ([]byte, error)
+	DeleteMFAChallenge(token string) error
+	IncrementAttempts(key string, window time.Duration) (int64, error)
+	DeleteAttempts(key string) error
+	IncrementLockCount(key string, window time.Duration) (int64, error)
+	SetLock(key string, exp time.Duration) error
+	GetLockTTL(key string) (time.Duration, error)
+	DenyAccessToken(jti string, exp time.Duration) error
+	IsAccessTokenDenied(jti string) (bool, error)
+	SetTokensValidAfter(userID string, validAfter time.Time, exp time.Duration) error
+	GetTokensValidAfter(userID string) (time.Time, error)
 }
 
 type Redis struct {
@@ -30,13 +58,13 @@ func NewRedis(cfg *config.Config) RedisItf {
 	}
 }
 
-func (r *Redis) SetOTP(email string, otp string, exp time.Duration) error {
-	key := "otp:" + email
+func (r *Redis) SetOTP(purpose OTPPurpose, email string, otp string, exp time.Duration) error {
+	key := "otp:" + string(purpose) + ":" + email
 	return r.store.Set(key, []byte(otp), exp)
 }
 
-func (r *Redis) GetOTP(email string) (string, error) {
-	key := "otp:" + email
+func (r *Redis) GetOTP(purpose OTPPurpose, email string) (string, error) {
+	key := "otp:" + string(purpose) + ":" + email
 	val, err := r.store.Get(key)
 	if err != nil {
 		return "", err
@@ -45,8 +73,42 @@ func (r *Redis) GetOTP(email string) (string, error) {
 	return string(val), nil
 }
 
-func (r *Redis) DeleteOTP(email string) error {
-	key := "otp:" + email
+func (r *Redis) DeleteOTP(purpose OTPPurpose, email string) error {
+	key := "otp:" + string(purpose) + ":" + email
+	return r.store.Delete(key)
+}
+
+func (r *Redis) SetOTPCooldown(purpose OTPPurpose, email string, exp time.Duration) error {
+	key := "otpcooldown:" + string(purpose) + ":" + email
+	return r.store.Set(key, []byte("1"), exp)
+}
+
+func (r *Redis) GetOTPCooldownTTL(purpose OTPPurpose, email string) (time.Duration, error) {
+	key := "otpcooldown:" + string(purpose) + ":" + email
+	return r.ttl(key)
+}
+
+func (r *Redis) IncrementOTPDailyCount(email string) (int64, error) {
+	return r.increment("otpdaily:"+email, 24*time.Hour)
+}
+
+func (r *Redis) SetPendingEmail(userID string, email string, exp time.Duration) error {
+	key := "pendingemail:" + userID
+	return r.store.Set(key, []byte(email), exp)
+}
+
+func (r *Redis) GetPendingEmail(userID string) (string, error) {
+	key := "pendingemail:" + userID
+	val, err := r.store.Get(key)
+	if err != nil {
+		return "", err
+	}
+
+	return string(val), nil
+}
+
+func (r *Redis) DeletePendingEmail(userID string) error {
+	key := "pendingemail:" + userID
 	return r.store.Delete(key)
 }
 
@@ -69,3 +131,108 @@ func (r *Redis) DeleteOAuthState(state string) error {
 	key := "gstate:" + state
 	return r.store.Delete(key)
 }
+
+func (r *Redis) SetMFAChallenge(token string, value []byte, exp time.Duration) error {
+	key := "mfa:" + token
+	return r.store.Set(key, value, exp)
+}
+
+func (r *Redis) GetMFAChallenge(token string) ([]byte, error) {
+	key := "mfa:" + token
+	val, err := r.store.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+func (r *Redis) DeleteMFAChallenge(token string) error {
+	key := "mfa:" + token
+	return r.store.Delete(key)
+}
+
+func (r *Redis) IncrementAttempts(key string, window time.Duration) (int64, error) {
+	return r.increment("attempts:"+key, window)
+}
+
+func (r *Redis) DeleteAttempts(key string) error {
+	key = "attempts:" + key
+	return r.store.Delete(key)
+}
+
+func (r *Redis) IncrementLockCount(key string, window time.Duration) (int64, error) {
+	return r.increment("lockcount:"+key, window)
+}
+
+func (r *Redis) SetLock(key string, exp time.Duration) error {
+	key = "lock:" + key
+	return r.store.Set(key, []byte("1"), exp)
+}
+
+func (r *Redis) GetLockTTL(key string) (time.Duration, error) {
+	return r.ttl("lock:" + key)
+}
+
+func (r *Redis) DenyAccessToken(jti string, exp time.Duration) error {
+	key := "denied:" + jti
+	return r.store.Set(key, []byte("1"), exp)
+}
+
+func (r *Redis) IsAccessTokenDenied(jti string) (bool, error) {
+	key := "denied:" + jti
+	val, err := r.store.Get(key)
+	if err != nil {
+		return false, err
+	}
+
+	return val != nil, nil
+}
+
+// SetTokensValidAfter stores the watermark with sub-second precision so it can
+// be compared with the millisecond timestamp in access token IDs.
+func (r *Redis) SetTokensValidAfter(userID string, validAfter time.Time, exp time.Duration) error {
+	key := "validafter:" + userID
+	return r.store.Set(key, []byte(strconv.FormatInt(validAfter.UnixMicro(), 10)), exp)
+}
+
+func (r *Redis) GetTokensValidAfter(userID string) (time.Time, error) {
+	key := "validafter:" + userID
+	val, err := r.store.Get(key)
+	if err != nil || val == nil {
+		return time.Time{}, err
+	}
+
+	micros, err := strconv.ParseInt(string(val), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.UnixMicro(micros), nil
+}
+
+func (r *Redis) ttl(key string) (time.Duration, error) {
+	ttl, err := r.store.Conn().TTL(context.Background(), key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	if ttl < 0 {
+		return 0, nil
+	}
+
+	return ttl, nil
+}
+
+func (r *Redis) increment(key string, window time.Duration) (int64, error) {
+	ctx := context.Background()
+
+	pipe := r.store.Conn().TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, window)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
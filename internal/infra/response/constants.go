@@ -10,44 +10,191 @@ const (
 	UserNotVerified     = "User not verified"
 	OAuthStateNotFound  = "OAuth state not found"
 	OAuthStateInvalid   = "OAuth state invalid"
+	SessionNotFound     = "Session not found"
+	SessionLimitReached = "Maximum number of active sessions reached"
+	RefreshTokenReused  = "Refresh token reuse detected, session has been revoked"
+	InvalidMFAToken     = "Invalid or expired MFA token"
+	InvalidMFACode      = "Invalid MFA code"
+	MFAAlreadyEnabled   = "MFA is already enabled"
+	MFANotEnabled       = "MFA is not enabled"
+	MFASetupRequired    = "MFA setup has not been started"
+	MFAUnavailable      = "MFA is not available on this server"
+	TooManyAttempts     = "Too many failed attempts. Please try again later"
+	OTPResendCooldown   = "Please wait before requesting another code"
+	OTPDailyLimit       = "Daily code request limit reached"
+	UserAlreadyVerified = "User already verified"
+	SameEmail           = "New email must be different from the current email"
+	NoPendingEmail      = "No pending email change"
 
-	FailedFindUser           = "Failed to find user"
-	FailedCreateUser         = "Failed to create user"
-	FailedUpdateUser         = "Failed to update user"
-	FailedAddRefreshToken    = "Failed to add refresh token"
-	FailedGetRefreshTokens   = "Failed to get refresh tokens"
-	FailedRemoveRefreshToken = "Failed to remove refresh token"
-	FailedExchangeOAuthToken = "Failed to exchange OAuth token"
-	FailedGetOAuthProfile    = "Failed to get OAuth profile"
-	FailedGoogleLogin        = "Failed to initiate Google login"
-
-	RegisterSuccess     = "Registration successful. OTP has been sent to email"
-	VerifyOTPSuccess    = "Verification successful"
-	LoginSuccess        = "Login successful"
-	RefreshTokenSuccess = "Token refresh successful"
-	LogoutSuccess       = "Logout successful"
+	FailedFindUser            = "Failed to find user"
+	FailedCreateUser          = "Failed to create user"
+	FailedUpdateUser          = "Failed to update user"
+	FailedAddRefreshToken     = "Failed to add refresh token"
+	FailedGetRefreshTokens    = "Failed to get refresh tokens"
+	FailedRemoveRefreshToken  = "Failed to remove refresh token"
+	FailedExchangeOAuthToken  = "Failed to exchange OAuth token"
+	FailedGetOAuthProfile     = "Failed to get OAuth profile"
+	FailedGoogleLogin         = "Failed to initiate Google login"
+	FailedRevokeSessions      = "Failed to revoke sessions"
+	FailedGetSessions         = "Failed to get sessions"
+	FailedRecordSecurityEvent = "Failed to record security event"
+	FailedGenerateMFASecret   = "Failed to generate MFA secret"
+	FailedStoreMFAChallenge   = "Failed to store MFA challenge"
+	FailedDeleteMFAChallenge  = "Failed to delete MFA challenge"
+	FailedUpdateMFA           = "Failed to update MFA settings"
+	FailedGenerateRecovery    = "Failed to generate recovery codes"
+	FailedVerifyMFA           = "Failed to verify MFA code"
+	FailedTrackAttempts       = "Failed to track authentication attempts"
+	FailedStorePendingEmail   = "Failed to store pending email change"
+	FailedSendNoticeEmail     = "Failed to send notice email"
+
+	RegisterSuccess       = "Registration successful. OTP has been sent to email"
+	VerifyOTPSuccess      = "Verification successful"
+	LoginSuccess          = "Login successful"
+	RefreshTokenSuccess   = "Token refresh successful"
+	LogoutSuccess         = "Logout successful"
+	ForgotPasswordSuccess = "If the email is registered, a password reset code has been sent to it"
+	ResendOTPSuccess      = "OTP has been resent to email"
+	ChangeEmailSuccess    = "Confirmation code has been sent to the new email"
+	ConfirmEmailSuccess   = "Email changed successfully"
+	ResetPasswordSuccess  = "Password reset successful"
+	RevokeSessionSuccess  = "Session revoked successfully"
+	LogoutAllSuccess      = "Logged out from all sessions"
+	MFARequiredSuccess    = "MFA verification required"
+	MFASetupSuccess       = "MFA setup started. Confirm with a code from your authenticator app"
+	MFAEnableSuccess      = "MFA enabled successfully"
+	MFADisableSuccess     = "MFA disabled successfully"
 )
 
 // challenge Domain
 const (
-	ChallengeNotFound         = "Challenge not found"
-	ChallengeAlreadyTaken     = "Challenge already taken"
-	ChallengeNotTaken         = "Challenge not taken by user"
-	ChallengeAlreadyCompleted = "Challenge already completed"
-	ChallengeNotActive        = "Challenge is not active"
-
-	FailedGetChallenges     = "Failed to get challenges"
-	FailedGetUserChallenges = "Failed to get user challenges"
-	FailedTakeChallenge     = "Failed to take challenge"
-	FailedCompleteChallenge = "Failed to complete challenge"
-	FailedUpdateUserExp     = "Failed to update user experience"
-	FailedGetBadges         = "Failed to get badges"
-	FailedGetUserBadges     = "Failed to get user badges"
-	FailedUnlockBadge       = "Failed to unlock badge"
-
-	TakeChallengeSuccess     = "Challenge taken successfully"
-	CompleteChallengeSuccess = "Challenge completed successfully"
-	BadgeUnlockedSuccess     = "New badge unlocked!"
+	ChallengeNotFound            = "Challenge not found"
+	ChallengeAlreadyTaken        = "Challenge already taken"
+	ChallengeNotTaken            = "Challenge not taken by user"
+	ChallengeAlreadyCompleted    = "Challenge already completed"
+	ChallengeCompletedThisPeriod = "Challenge already completed for the current period"
+	ChallengeNotActive           = "Challenge is not active"
+	ChallengeOverdue             = "Challenge deadline has passed"
+	ChallengeRetakeCooldown      = "Please wait before retaking this challenge"
+	ChallengeNotOngoing          = "Challenge is not ongoing"
+	ChallengeUnderReview         = "Challenge proof is already under review"
+	ProofPhotoRequired           = "This challenge requires a photo as proof"
+	ProofNoteRequired            = "This challenge requires a note as proof"
+	SubmissionNotFound           = "Submission not found"
+	SubmissionAlreadyReviewed    = "Submission has already been reviewed"
+	CannotReviewOwnSubmission    = "You cannot review your own submission"
+	OngoingChallengeLimit        = "Maximum number of ongoing challenges reached"
+	BadgeNotFound                = "Badge not found"
+	BadgeTypeAlreadyExists       = "Badge type already exists"
+	ImageRequired                = "Image file is required"
+	ImageTooLarge                = "Image file is too large"
+	UnsupportedImageType         = "Image must be a PNG, JPEG or WebP file"
+	UnsupportedProofImageType    = "Proof photo must be a JPEG or PNG image"
+	TrackRequired                = "This challenge requires a GPX or FIT track"
+	TrackTooLarge                = "Track file is too large"
+	UnsupportedTrackFormat       = "Track must be a GPX or FIT file"
+	InvalidTrack                 = "Track file could not be read or has no timed points"
+	TrackTooShort                = "Track is too short to count for this challenge"
+	TrackLooksMotorized          = "Track speed suggests motorized travel"
+	TrackAlreadyUsed             = "This track has already been submitted"
+	TrackOutsideAttempt          = "Track was not recorded during this challenge attempt"
+	ProofImageNotFound           = "Proof image not found"
+
+	FailedGetChallenges         = "Failed to get challenges"
+	FailedGetUserChallenges     = "Failed to get user challenges"
+	FailedTakeChallenge         = "Failed to take challenge"
+	FailedCompleteChallenge     = "Failed to complete challenge"
+	FailedUpdateUserExp         = "Failed to update user experience"
+	FailedGetBadges             = "Failed to get badges"
+	FailedGetUserBadges         = "Failed to get user badges"
+	FailedUnlockBadge           = "Failed to unlock badge"
+	FailedCreateChallenge       = "Failed to create challenge"
+	FailedUpdateChallenge       = "Failed to update challenge"
+	FailedDeleteChallenge       = "Failed to delete challenge"
+	FailedCreateBadge           = "Failed to create badge"
+	FailedUpdateBadge           = "Failed to update badge"
+	FailedDeleteBadge           = "Failed to delete badge"
+	FailedUploadImage           = "Failed to upload image"
+	FailedBackfillBadges        = "Failed to backfill badges"
+	FailedFailOverdueChallenges = "Failed to fail overdue challenges"
+	FailedAbandonChallenge      = "Failed to abandon challenge"
+	FailedSubmitProof           = "Failed to submit challenge proof"
+	FailedGetSubmissions        = "Failed to get submissions"
+	FailedReviewSubmission      = "Failed to review submission"
+	FailedRecordImpact          = "Failed to record challenge impact"
+	FailedReadTrack             = "Failed to read track file"
+	FailedSaveTrack             = "Failed to save track summary"
+	FailedGetProofImage         = "Failed to get proof image"
+
+	TakeChallengeSuccess       = "Challenge taken successfully"
+	CompleteChallengeSuccess   = "Challenge completed successfully"
+	AbandonChallengeSuccess    = "Challenge abandoned"
+	SubmitProofSuccess         = "Proof submitted and awaiting review"
+	ApproveSubmissionSuccess   = "Submission approved"
+	RejectSubmissionSuccess    = "Submission rejected"
+	BadgeUnlockedSuccess       = "New badge unlocked!"
+	LevelUpSuccess             = "Level up!"
+	CreateChallengeSuccess     = "Challenge created successfully"
+	UpdateChallengeSuccess     = "Challenge updated successfully"
+	ActivateChallengeSuccess   = "Challenge activated successfully"
+	DeactivateChallengeSuccess = "Challenge deactivated successfully"
+	DeleteChallengeSuccess     = "Challenge deleted successfully"
+	CreateBadgeSuccess         = "Badge created successfully"
+	UpdateBadgeSuccess         = "Badge updated successfully"
+	DeleteBadgeSuccess         = "Badge deleted successfully"
+	UploadBadgeImageSuccess    = "Badge image uploaded successfully"
+	BackfillBadgesSuccess      = "Badges backfilled successfully"
+)
+
+// Impact Domain
+const (
+	FailedGetImpact = "Failed to get impact"
+)
+
+// Footprint Domain
+const (
+	UnsupportedTransportMode   = "Unsupported transport mode"
+	UnsupportedDiet            = "Unsupported diet"
+	UnsupportedRegion          = "Electricity emission factors are not available for this region"
+	EmissionFactorsUnavailable = "Emission factors are not available"
+
+	FailedGetEmissionFactors = "Failed to get emission factors"
+	FailedCreateAssessment   = "Failed to create footprint assessment"
+	FailedGetAssessments     = "Failed to get footprint assessments"
+
+	CreateAssessmentSuccess = "Footprint calculated successfully"
+)
+
+// Activity Domain
+const (
+	ActivityNotFound         = "Activity not found"
+	UnsupportedActivityType  = "Unsupported activity type"
+	ActivityModeNotAllowed   = "Only transport activities have a mode"
+	ActivityQuantityTooLarge = "Activity quantity is too large"
+	ActivityInFuture         = "Activity date cannot be in the future"
+
+	FailedCreateActivity   = "Failed to create activity"
+	FailedGetActivities    = "Failed to get activities"
+	FailedUpdateActivity   = "Failed to update activity"
+	FailedDeleteActivity   = "Failed to delete activity"
+	FailedGetActivityTrend = "Failed to get activity trend"
+
+	CreateActivitySuccess = "Activity logged successfully"
+	UpdateActivitySuccess = "Activity updated successfully"
+	DeleteActivitySuccess = "Activity deleted successfully"
+)
+
+// User Domain
+const (
+	CurrentPasswordRequired = "Current password is required"
+	InvalidCurrentPassword  = "Current password is incorrect"
+	CannotChangeOwnRole     = "You cannot change your own role"
+
+	FailedUpdateRole = "Failed to update user role"
+
+	ChangePasswordSuccess = "Password changed successfully"
+	SetPasswordSuccess    = "Password set successfully"
+	UpdateRoleSuccess     = "User role updated successfully"
 )
 
 // Others
@@ -57,6 +204,7 @@ const (
 	FailedStoreOTP             = "Failed to store OTP"
 	FailedDeleteOTP            = "Failed to delete OTP"
 	FailedSendOTPEmail         = "Failed to send OTP email"
+	FailedSendResetEmail       = "Failed to send password reset email"
 	FailedGenerateRefreshToken = "Failed to generate refresh token"
 	FailedGenerateAccessToken  = "Failed to generate access token"
 	FailedGenerateOAuthState   = "Failed to generate OAuth state"
@@ -74,4 +222,8 @@ const (
 	MissingAccessToken          = "Missing access token"
 	InvalidAccessToken          = "Invalid access token"
 	InvalidOrMissingBearerToken = "Invalid or missing bearer token"
+	RevokedAccessToken          = "Access token has been revoked"
+	FailedCheckTokenRevocation  = "Failed to check access token revocation"
+	FailedRevokeAccessToken     = "Failed to revoke access token"
+	InsufficientRole            = "You do not have permission to access this resource"
 )
@@ -43,6 +43,14 @@ func ErrConflict(message ...string) *Err {
 	return newError(fiber.StatusConflict, "Conflict", message...)
 }
 
+func ErrTooManyRequests(message ...string) *Err {
+	return newError(fiber.StatusTooManyRequests, "Too Many Requests", message...)
+}
+
+func ErrServiceUnavailable(message ...string) *Err {
+	return newError(fiber.StatusServiceUnavailable, "Service Unavailable", message...)
+}
+
 var validationMessages = map[string]string{
 	"required": "The {field} field is required.",
 	"email":    "The {field} field must be a valid email format.",
@@ -50,6 +58,14 @@ var validationMessages = map[string]string{
 	"max":      "The {field} field must be at most {param} characters long.",
 	"uuid":     "The {field} field must be a valid UUID format.",
 	"numeric":  "The {field} field must be a number.",
+	"oneof":    "The {field} field must be one of: {param}.",
+	"gte":      "The {field} field must be greater than or equal to {param}.",
+	"lte":      "The {field} field must be less than or equal to {param}.",
+	"gt":       "The {field} field must be greater than {param}.",
+	"datetime": "The {field} field must be a valid date in YYYY-MM-DD format.",
+
+	"required_if":     "The {field} field is required.",
+	"excluded_unless": "The {field} field is not allowed for this type.",
 }
 
 func ErrValidation(errs validator.ValidationErrors) *Err {
@@ -1,7 +1,7 @@
 package response
 
 type Err struct {
-	Code    int    `json:-`
+	Code    int    `json:"-"`
 	Message string `json:"message"`
 	Payload any    `json:"payload,omitempty"`
 }
@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+type SchedulerItf interface {
+	Every(name string, interval time.Duration, job func() error)
+	Start()
+	Stop()
+}
+
+type job struct {
+	name     string
+	interval time.Duration
+	run      func() error
+}
+
+type Scheduler struct {
+	jobs []job
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewScheduler() SchedulerItf {
+	return &Scheduler{
+		stop: make(chan struct{}),
+	}
+}
+
+// Every registers a job to run once per interval after Start. Runs of the
+// same job never overlap; a slow run simply delays the next tick.
+func (s *Scheduler) Every(name string, interval time.Duration, run func() error) {
+	s.jobs = append(s.jobs, job{
+		name:     name,
+		interval: interval,
+		run:      run,
+	})
+}
+
+func (s *Scheduler) Start() {
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go s.loop(j)
+	}
+}
+
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(j job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.run(j)
+		}
+	}
+}
+
+func (s *Scheduler) run(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Scheduled job %s panicked: %v", j.name, r)
+		}
+	}()
+
+	if err := j.run(); err != nil {
+		log.Printf("Scheduled job %s failed: %v", j.name, err)
+	}
+}
@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ablebil/eco-sample/config"
+)
+
+type StorageItf interface {
+	Upload(key string, content io.Reader) (string, error)
+	Delete(url string) error
+	Open(url string) (io.ReadCloser, error)
+}
+
+type LocalStorage struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStorage(cfg *config.Config) StorageItf {
+	return &LocalStorage{
+		dir:     cfg.StorageDir,
+		baseURL: strings.TrimRight(cfg.AppURL, "/") + cfg.StoragePublicPath,
+	}
+}
+
+// NewPrivateStorage keeps files outside the public static root. Uploads return
+// the bare storage key, which callers must serve through their own routes.
+func NewPrivateStorage(cfg *config.Config) StorageItf {
+	return &LocalStorage{
+		dir: cfg.PrivateStorageDir,
+	}
+}
+
+func (s *LocalStorage) Upload(key string, content io.Reader) (string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return s.url(key), nil
+}
+
+// Delete ignores URLs that were not produced by this storage, such as the
+// placeholder images of seeded badges.
+func (s *LocalStorage) Delete(url string) error {
+	key, ok := s.key(url)
+	if !ok {
+		return nil
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) Open(url string) (io.ReadCloser, error) {
+	key, ok := s.key(url)
+	if !ok {
+		return nil, os.ErrNotExist
+	}
+
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (s *LocalStorage) url(key string) string {
+	if s.baseURL == "" {
+		return key
+	}
+
+	return s.baseURL + "/" + key
+}
+
+func (s *LocalStorage) key(url string) (string, bool) {
+	if s.baseURL == "" {
+		return url, true
+	}
+
+	return strings.CutPrefix(url, s.baseURL+"/")
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", errors.New("invalid storage key")
+	}
+
+	return filepath.Join(s.dir, key), nil
+}
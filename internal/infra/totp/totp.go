@@ -0,0 +1,193 @@
+package totp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ablebil/eco-sample/config"
+)
+
+const (
+	secretSize = 20
+	digits     = 6
+	period     = 30 * time.Second
+	skew       = 1
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var (
+	ErrMissingEncryptionKey = errors.New("totp: MFA_ENCRYPTION_KEY is not set")
+	ErrInvalidEncryptionKey = errors.New("totp: MFA_ENCRYPTION_KEY must be a base64 encoded 32-byte key")
+	ErrInvalidCiphertext    = errors.New("totp: malformed encrypted secret")
+)
+
+type TOTPItf interface {
+	GenerateSecret() (string, error)
+	GenerateURI(secret, accountName string) string
+	GenerateCode(secret string, t time.Time) (string, error)
+	Validate(secret, code string, lastStep int64) (int64, bool)
+	EncryptSecret(secret string) (string, error)
+	DecryptSecret(encrypted string) (string, error)
+}
+
+type TOTP struct {
+	issuer string
+	aead   cipher.AEAD
+}
+
+// NewTOTP returns ErrMissingEncryptionKey when no key is configured, which
+// callers may treat as MFA being turned off. A key can be generated with
+// `openssl rand -base64 32`.
+func NewTOTP(cfg *config.Config) (TOTPItf, error) {
+	if cfg.MFAEncryptionKey == "" {
+		return nil, ErrMissingEncryptionKey
+	}
+
+	key, err := base64.StdEncoding.DecodeString(cfg.MFAEncryptionKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrInvalidEncryptionKey
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TOTP{
+		issuer: cfg.MFAIssuer,
+		aead:   aead,
+	}, nil
+}
+
+func (t *TOTP) GenerateSecret() (string, error) {
+	bytes := make([]byte, secretSize)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	return encoding.EncodeToString(bytes), nil
+}
+
+func (t *TOTP) GenerateURI(secret, accountName string) string {
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", t.issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(digits))
+	params.Set("period", fmt.Sprint(int(period.Seconds())))
+
+	label := url.PathEscape(t.issuer + ":" + accountName)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+func (t *TOTP) GenerateCode(secret string, at time.Time) (string, error) {
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", err
+	}
+
+	return hotp(key, uint64(timeStep(at)), digits), nil
+}
+
+// Validate checks code against the current time step and its neighbours and
+// returns the step it matched. Steps at or before lastStep are skipped so a
+// code that has already been accepted cannot be replayed (RFC 6238 §5.2).
+func (t *TOTP) Validate(secret, code string, lastStep int64) (int64, bool) {
+	return t.validateAt(secret, code, time.Now(), lastStep)
+}
+
+func (t *TOTP) validateAt(secret, code string, now time.Time, lastStep int64) (int64, bool) {
+	if len(code) != digits {
+		return 0, false
+	}
+
+	key, err := encoding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	current := timeStep(now)
+	for i := -skew; i <= skew; i++ {
+		step := current + int64(i)
+		if step <= lastStep {
+			continue
+		}
+
+		expected := hotp(key, uint64(step), digits)
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// EncryptSecret seals a secret with AES-GCM so it can be stored at rest. The
+// random nonce is prepended to the ciphertext.
+func (t *TOTP) EncryptSecret(secret string) (string, error) {
+	nonce := make([]byte, t.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := t.aead.Seal(nonce, nonce, []byte(secret), nil)
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (t *TOTP) DecryptSecret(encrypted string) (string, error) {
+	sealed, err := base64.RawStdEncoding.DecodeString(encrypted)
+	if err != nil || len(sealed) < t.aead.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	nonce, ciphertext := sealed[:t.aead.NonceSize()], sealed[t.aead.NonceSize():]
+	secret, err := t.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+
+	return string(secret), nil
+}
+
+func timeStep(at time.Time) int64 {
+	return at.Unix() / int64(period.Seconds())
+}
+
+// hotp implements the HOTP algorithm from RFC 4226 with HMAC-SHA1, which
+// RFC 6238 applies to a time-based counter.
+func hotp(key []byte, counter uint64, digits int) string {
+	msg := make([]byte, 8)
+	binary.BigEndian.PutUint64(msg, counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
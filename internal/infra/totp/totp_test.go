@@ -0,0 +1,187 @@
+package totp
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/Ablebil/eco-sample/config"
+)
+
+// rfcKey is the SHA-1 seed used by the RFC 6238 Appendix B test vectors.
+var rfcKey = []byte("12345678901234567890")
+
+func newTestTOTP(t *testing.T) *TOTP {
+	t.Helper()
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatal(err)
+	}
+
+	itf, err := NewTOTP(&config.Config{
+		MFAIssuer:        "Eco Trace",
+		MFAEncryptionKey: base64.StdEncoding.EncodeToString(key),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return itf.(*TOTP)
+}
+
+func TestRFC6238Vectors(t *testing.T) {
+	tt := newTestTOTP(t)
+	secret := encoding.EncodeToString(rfcKey)
+
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "94287082"},
+		{1111111109, "07081804"},
+		{1111111111, "14050471"},
+		{1234567890, "89005924"},
+		{2000000000, "69279037"},
+		{20000000000, "65353130"},
+	}
+
+	for _, tc := range tests {
+		at := time.Unix(tc.unix, 0)
+
+		if got := hotp(rfcKey, uint64(timeStep(at)), 8); got != tc.want {
+			t.Errorf("hotp at T=%d = %s, want %s", tc.unix, got, tc.want)
+		}
+
+		code, err := tt.GenerateCode(secret, at)
+		if err != nil {
+			t.Fatalf("GenerateCode at T=%d: %v", tc.unix, err)
+		}
+
+		if want := tc.want[len(tc.want)-digits:]; code != want {
+			t.Errorf("GenerateCode at T=%d = %s, want %s", tc.unix, code, want)
+		}
+	}
+}
+
+func TestValidateSkewWindow(t *testing.T) {
+	tt := newTestTOTP(t)
+	secret := encoding.EncodeToString(rfcKey)
+	now := time.Unix(1234567890, 0)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		valid  bool
+	}{
+		{"current step", 0, true},
+		{"previous step", -period, true},
+		{"next step", period, true},
+		{"two steps behind", -2 * period, false},
+		{"two steps ahead", 2 * period, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			code, err := tt.GenerateCode(secret, now.Add(tc.offset))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			step, ok := tt.validateAt(secret, code, now, 0)
+			if ok != tc.valid {
+				t.Fatalf("validateAt = %v, want %v", ok, tc.valid)
+			}
+
+			if ok && step != timeStep(now.Add(tc.offset)) {
+				t.Errorf("matched step %d, want %d", step, timeStep(now.Add(tc.offset)))
+			}
+		})
+	}
+}
+
+func TestValidateRejectsUsedSteps(t *testing.T) {
+	tt := newTestTOTP(t)
+	secret := encoding.EncodeToString(rfcKey)
+	now := time.Unix(1234567890, 0)
+
+	code, err := tt.GenerateCode(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step, ok := tt.validateAt(secret, code, now, 0)
+	if !ok {
+		t.Fatal("first use of a valid code was rejected")
+	}
+
+	if _, ok := tt.validateAt(secret, code, now, step); ok {
+		t.Error("the same code was accepted twice")
+	}
+
+	if _, ok := tt.validateAt(secret, code, now.Add(period), step); ok {
+		t.Error("a used code was accepted again inside the skew window")
+	}
+
+	previous, err := tt.GenerateCode(secret, now.Add(-period))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tt.validateAt(secret, previous, now, step); ok {
+		t.Error("a code older than the last accepted step was accepted")
+	}
+
+	next, err := tt.GenerateCode(secret, now.Add(period))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := tt.validateAt(secret, next, now, step); !ok {
+		t.Error("a code for a later step was rejected")
+	}
+}
+
+func TestEncryptSecret(t *testing.T) {
+	tt := newTestTOTP(t)
+
+	secret, err := tt.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := tt.EncryptSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if encrypted == secret {
+		t.Fatal("secret was stored in plaintext")
+	}
+
+	decrypted, err := tt.DecryptSecret(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decrypted != secret {
+		t.Errorf("DecryptSecret = %q, want %q", decrypted, secret)
+	}
+
+	if _, err := newTestTOTP(t).DecryptSecret(encrypted); err == nil {
+		t.Error("secret decrypted with a different key")
+	}
+}
+
+func TestNewTOTPRejectsInvalidKey(t *testing.T) {
+	if _, err := NewTOTP(&config.Config{}); err != ErrMissingEncryptionKey {
+		t.Errorf("NewTOTP without a key error = %v, want ErrMissingEncryptionKey", err)
+	}
+
+	for _, key := range []string{"not base64", base64.StdEncoding.EncodeToString([]byte("short"))} {
+		if _, err := NewTOTP(&config.Config{MFAEncryptionKey: key}); err != ErrInvalidEncryptionKey {
+			t.Errorf("NewTOTP(%q) error = %v, want ErrInvalidEncryptionKey", key, err)
+		}
+	}
+}
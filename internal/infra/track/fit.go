@@ -0,0 +1,237 @@
+package track
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// FIT is Garmin's binary activity format. Only what is needed to rebuild the
+// track is decoded: definition messages, and the timestamp and position
+// fields of record messages.
+const (
+	fitRecordMessage = 20
+
+	fitFieldTimestamp = 253
+	fitFieldLat       = 0
+	fitFieldLong      = 1
+
+	fitInvalidSint32 = 0x7FFFFFFF
+	fitInvalidUint32 = 0xFFFFFFFF
+)
+
+// fitEpoch is the zero of FIT timestamps, 1989-12-31T00:00:00Z.
+var fitEpoch = time.Date(1989, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+type fitField struct {
+	num  byte
+	size int
+}
+
+type fitDefinition struct {
+	order   binary.ByteOrder
+	global  uint16
+	fields  []fitField
+	devSize int
+}
+
+func isFIT(data []byte) bool {
+	return len(data) >= 12 && string(data[8:12]) == ".FIT"
+}
+
+func parseFIT(data []byte) ([]Point, error) {
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize {
+		return nil, ErrMalformedTrack
+	}
+
+	end := headerSize + int(binary.LittleEndian.Uint32(data[4:8]))
+	if end > len(data) {
+		return nil, ErrMalformedTrack
+	}
+
+	definitions := make(map[byte]*fitDefinition)
+	var points []Point
+	var lastTimestamp uint32
+
+	pos := headerSize
+	for pos < end {
+		header := data[pos]
+		pos++
+
+		// Compressed timestamp header: a record message whose timestamp is
+		// a 5-bit offset from the previous one.
+		if header&0x80 != 0 {
+			local := (header >> 5) & 0x03
+			offset := uint32(header & 0x1F)
+
+			timestamp := lastTimestamp&^0x1F | offset
+			if offset < lastTimestamp&0x1F {
+				timestamp += 0x20
+			}
+			lastTimestamp = timestamp
+
+			definition, ok := definitions[local]
+			if !ok {
+				return nil, ErrMalformedTrack
+			}
+
+			point, next, err := readFITData(data, pos, end, definition, &lastTimestamp, true)
+			if err != nil {
+				return nil, err
+			}
+			pos = next
+
+			if point != nil {
+				points = append(points, *point)
+			}
+			continue
+		}
+
+		local := header & 0x0F
+
+		if header&0x40 != 0 {
+			definition, next, err := readFITDefinition(data, pos, end, header&0x20 != 0)
+			if err != nil {
+				return nil, err
+			}
+			definitions[local] = definition
+			pos = next
+			continue
+		}
+
+		definition, ok := definitions[local]
+		if !ok {
+			return nil, ErrMalformedTrack
+		}
+
+		point, next, err := readFITData(data, pos, end, definition, &lastTimestamp, false)
+		if err != nil {
+			return nil, err
+		}
+		pos = next
+
+		if point != nil {
+			points = append(points, *point)
+		}
+	}
+
+	return points, nil
+}
+
+func readFITDefinition(data []byte, pos, end int, hasDevFields bool) (*fitDefinition, int, error) {
+	if pos+5 > end {
+		return nil, 0, ErrMalformedTrack
+	}
+
+	definition := &fitDefinition{order: binary.LittleEndian}
+	if data[pos+1] == 1 {
+		definition.order = binary.BigEndian
+	}
+	definition.global = definition.order.Uint16(data[pos+2 : pos+4])
+	count := int(data[pos+4])
+	pos += 5
+
+	if pos+count*3 > end {
+		return nil, 0, ErrMalformedTrack
+	}
+
+	for i := 0; i < count; i++ {
+		definition.fields = append(definition.fields, fitField{
+			num:  data[pos],
+			size: int(data[pos+1]),
+		})
+		pos += 3
+	}
+
+	if hasDevFields {
+		if pos >= end {
+			return nil, 0, ErrMalformedTrack
+		}
+
+		devCount := int(data[pos])
+		pos++
+
+		if pos+devCount*3 > end {
+			return nil, 0, ErrMalformedTrack
+		}
+
+		for i := 0; i < devCount; i++ {
+			definition.devSize += int(data[pos+1])
+			pos += 3
+		}
+	}
+
+	return definition, pos, nil
+}
+
+// readFITData consumes one data message and returns a point when it is a
+// record with a timestamp and a valid position.
+func readFITData(data []byte, pos, end int, definition *fitDefinition, lastTimestamp *uint32, compressed bool) (*Point, int, error) {
+	hasTimestamp := compressed
+	var lat, lon int32
+	hasLat, hasLon := false, false
+
+	for _, field := range definition.fields {
+		if pos+field.size > end {
+			return nil, 0, ErrMalformedTrack
+		}
+		value := data[pos : pos+field.size]
+		pos += field.size
+
+		if field.size != 4 {
+			continue
+		}
+
+		raw := definition.order.Uint32(value)
+
+		// Every message's timestamp is the reference for later compressed
+		// timestamps, not only those of records.
+		if field.num == fitFieldTimestamp {
+			if raw != fitInvalidUint32 {
+				*lastTimestamp = raw
+				hasTimestamp = true
+			}
+			continue
+		}
+
+		if definition.global != fitRecordMessage {
+			continue
+		}
+
+		switch field.num {
+		case fitFieldLat:
+			if raw != fitInvalidSint32 {
+				lat, hasLat = int32(raw), true
+			}
+		case fitFieldLong:
+			if raw != fitInvalidSint32 {
+				lon, hasLon = int32(raw), true
+			}
+		}
+	}
+
+	if pos+definition.devSize > end {
+		return nil, 0, ErrMalformedTrack
+	}
+	pos += definition.devSize
+
+	if definition.global != fitRecordMessage || !hasTimestamp || !hasLat || !hasLon {
+		return nil, pos, nil
+	}
+
+	point := &Point{
+		Lat:  semicirclesToDegrees(lat),
+		Lon:  semicirclesToDegrees(lon),
+		Time: fitEpoch.Add(time.Duration(*lastTimestamp) * time.Second),
+	}
+
+	if !validCoordinate(point.Lat, point.Lon) {
+		return nil, pos, nil
+	}
+
+	return point, pos, nil
+}
+
+func semicirclesToDegrees(semicircles int32) float64 {
+	return float64(semicircles) * 180 / (1 << 31)
+}
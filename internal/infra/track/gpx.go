@@ -0,0 +1,49 @@
+package track
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+type gpxFile struct {
+	Tracks []struct {
+		Segments []struct {
+			Points []struct {
+				Lat  float64 `xml:"lat,attr"`
+				Lon  float64 `xml:"lon,attr"`
+				Time string  `xml:"time"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// parseGPX reads the timed points of every track segment. Routes and
+// waypoints carry no timing and are ignored.
+func parseGPX(data []byte) ([]Point, error) {
+	var file gpxFile
+	if err := xml.Unmarshal(data, &file); err != nil {
+		return nil, ErrMalformedTrack
+	}
+
+	var points []Point
+	segment := 0
+	for _, trk := range file.Tracks {
+		for _, seg := range trk.Segments {
+			for _, pt := range seg.Points {
+				if pt.Time == "" || !validCoordinate(pt.Lat, pt.Lon) {
+					continue
+				}
+
+				t, err := time.Parse(time.RFC3339, pt.Time)
+				if err != nil {
+					return nil, ErrMalformedTrack
+				}
+
+				points = append(points, Point{Lat: pt.Lat, Lon: pt.Lon, Time: t, Segment: segment})
+			}
+			segment++
+		}
+	}
+
+	return points, nil
+}
@@ -0,0 +1,152 @@
+package track
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported track format")
+	ErrMalformedTrack    = errors.New("malformed track file")
+	ErrTooFewPoints      = errors.New("track has too few timed points")
+)
+
+type Format string
+
+const (
+	FormatGPX Format = "gpx"
+	FormatFIT Format = "fit"
+)
+
+// speedWindow is the shortest stretch of time a speed is measured over, so a
+// single noisy GPS fix cannot produce a spike.
+const speedWindow = 60 * time.Second
+
+const earthRadiusMeters = 6371000
+
+type Point struct {
+	Lat     float64
+	Lon     float64
+	Time    time.Time
+	Segment int
+}
+
+type Summary struct {
+	Format         Format
+	Points         int
+	DistanceMeters float64
+	Duration       time.Duration
+	AvgSpeedKmh    float64
+	MaxSpeedKmh    float64
+	StartedAt      time.Time
+	EndedAt        time.Time
+}
+
+// Parse detects whether data is a FIT or GPX file and summarises the track it
+// contains. Nothing outside data is consulted.
+func Parse(data []byte) (*Summary, error) {
+	var format Format
+	var points []Point
+	var err error
+
+	switch {
+	case isFIT(data):
+		format = FormatFIT
+		points, err = parseFIT(data)
+	case isGPX(data):
+		format = FormatGPX
+		points, err = parseGPX(data)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := summarize(points)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Format = format
+	return summary, nil
+}
+
+func isGPX(data []byte) bool {
+	head := data[:min(len(data), 1024)]
+	return bytes.Contains(head, []byte("<gpx"))
+}
+
+func summarize(points []Point) (*Summary, error) {
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Time.Before(points[j].Time)
+	})
+
+	if len(points) < 2 || !points[len(points)-1].Time.After(points[0].Time) {
+		return nil, ErrTooFewPoints
+	}
+
+	// cumulative[i] is the distance covered up to points[i]; jumps between
+	// segments are not counted as travelled.
+	cumulative := make([]float64, len(points))
+	for i := 1; i < len(points); i++ {
+		cumulative[i] = cumulative[i-1]
+		if points[i].Segment == points[i-1].Segment {
+			cumulative[i] += haversine(points[i-1], points[i])
+		}
+	}
+
+	first, last := points[0], points[len(points)-1]
+	summary := &Summary{
+		Points:         len(points),
+		DistanceMeters: cumulative[len(cumulative)-1],
+		Duration:       last.Time.Sub(first.Time),
+		StartedAt:      first.Time,
+		EndedAt:        last.Time,
+	}
+
+	summary.AvgSpeedKmh = speedKmh(summary.DistanceMeters, summary.Duration)
+	summary.MaxSpeedKmh = summary.AvgSpeedKmh
+
+	j := 0
+	for i := range points {
+		for j < len(points) && points[j].Time.Sub(points[i].Time) < speedWindow {
+			j++
+		}
+
+		if j == len(points) {
+			break
+		}
+
+		speed := speedKmh(cumulative[j]-cumulative[i], points[j].Time.Sub(points[i].Time))
+		summary.MaxSpeedKmh = max(summary.MaxSpeedKmh, speed)
+	}
+
+	return summary, nil
+}
+
+func speedKmh(meters float64, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+
+	return meters / duration.Seconds() * 3.6
+}
+
+func haversine(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Lon - a.Lon) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func validCoordinate(lat, lon float64) bool {
+	return lat >= -90 && lat <= 90 && lon >= -180 && lon <= 180
+}
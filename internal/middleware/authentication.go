@@ -19,15 +19,25 @@ func (m *Middleware) Authentication(ctx *fiber.Ctx) error {
 		return res.ErrUnauthorized(res.InvalidOrMissingBearerToken)
 	}
 
-	userID, name, email, err := m.jwt.VerifyAccessToken(parts[1])
+	claims, err := m.jwt.VerifyAccessToken(parts[1])
 	if err != nil {
 		fmt.Println("Detailed Error:", err)
 		return res.ErrUnauthorized(res.InvalidAccessToken)
 	}
 
-	ctx.Locals("user_id", userID.String())
-	ctx.Locals("name", name)
-	ctx.Locals("email", email)
+	revoked, err := m.revocation.isRevoked(claims)
+	if err != nil {
+		return res.ErrInternalServerError(res.FailedCheckTokenRevocation)
+	}
+
+	if revoked {
+		return res.ErrUnauthorized(res.RevokedAccessToken)
+	}
+
+	ctx.Locals("user_id", claims.UserID.String())
+	ctx.Locals("name", claims.Name)
+	ctx.Locals("email", claims.Email)
+	ctx.Locals("role", claims.Role)
 
 	return ctx.Next()
 }
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"slices"
+
+	res "github.com/Ablebil/eco-sample/internal/infra/response"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Authorize must run after Authentication, which stores the role claim.
+func (m *Middleware) Authorize(roles ...string) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		role, ok := ctx.Locals("role").(string)
+		if !ok || !slices.Contains(roles, role) {
+			return res.ErrForbidden(res.InsufficientRole)
+		}
+
+		return ctx.Next()
+	}
+}
@@ -1,20 +1,25 @@
 package middleware
 
 import (
+	"github.com/Ablebil/eco-sample/config"
 	"github.com/Ablebil/eco-sample/internal/infra/jwt"
+	"github.com/Ablebil/eco-sample/internal/infra/redis"
 	"github.com/gofiber/fiber/v2"
 )
 
 type MiddlewareItf interface {
 	Authentication(ctx *fiber.Ctx) error
+	Authorize(roles ...string) fiber.Handler
 }
 
 type Middleware struct {
-	jwt jwt.JWTItf
+	jwt        jwt.JWTItf
+	revocation *revocationCache
 }
 
-func NewMiddleware(jwt jwt.JWTItf) MiddlewareItf {
+func NewMiddleware(jwt jwt.JWTItf, redis redis.RedisItf, cfg *config.Config) MiddlewareItf {
 	return &Middleware{
-		jwt: jwt,
+		jwt:        jwt,
+		revocation: newRevocationCache(redis, cfg.TokenRevocationCacheTTL),
 	}
 }
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Ablebil/eco-sample/internal/infra/jwt"
+	"github.com/Ablebil/eco-sample/internal/infra/redis"
+)
+
+const revocationCacheSweepSize = 10000
+
+type watermarkEntry struct {
+	validAfter time.Time
+	expiresAt  time.Time
+}
+
+// revocationCache keeps recent denylist and watermark lookups in memory so
+// that most authenticated requests skip the Redis round trip. Denied tokens
+// are remembered until they expire; everything else for at most ttl.
+type revocationCache struct {
+	redis redis.RedisItf
+	ttl   time.Duration
+
+	mu         sync.Mutex
+	denied     map[string]time.Time
+	allowed    map[string]time.Time
+	watermarks map[string]watermarkEntry
+}
+
+func newRevocationCache(redis redis.RedisItf, ttl time.Duration) *revocationCache {
+	return &revocationCache{
+		redis:      redis,
+		ttl:        ttl,
+		denied:     make(map[string]time.Time),
+		allowed:    make(map[string]time.Time),
+		watermarks: make(map[string]watermarkEntry),
+	}
+}
+
+func (c *revocationCache) isRevoked(claims *jwt.AccessClaims) (bool, error) {
+	denied, err := c.isDenied(claims)
+	if err != nil || denied {
+		return denied, err
+	}
+
+	if claims.IssuedAt == nil {
+		return true, nil
+	}
+
+	validAfter, err := c.validAfter(claims.UserID.String())
+	if err != nil {
+		return false, err
+	}
+
+	// A token issued at the watermark itself stays valid so that tokens handed
+	// out right after a revocation, e.g. by ConfirmEmailChange, keep working.
+	return claims.MintedAt().Before(validAfter.Truncate(time.Millisecond)), nil
+}
+
+func (c *revocationCache) isDenied(claims *jwt.AccessClaims) (bool, error) {
+	jti := claims.ID
+	if jti == "" {
+		return false, nil
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	if _, ok := c.denied[jti]; ok {
+		c.mu.Unlock()
+		return true, nil
+	}
+
+	if until, ok := c.allowed[jti]; ok && now.Before(until) {
+		c.mu.Unlock()
+		return false, nil
+	}
+	c.mu.Unlock()
+
+	denied, err := c.redis.IsAccessTokenDenied(jti)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep(now)
+	if denied {
+		expiresAt := now.Add(c.ttl)
+		if claims.ExpiresAt != nil {
+			expiresAt = claims.ExpiresAt.Time
+		}
+
+		c.denied[jti] = expiresAt
+	} else {
+		c.allowed[jti] = now.Add(c.ttl)
+	}
+
+	return denied, nil
+}
+
+func (c *revocationCache) validAfter(userID string) (time.Time, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	if entry, ok := c.watermarks[userID]; ok && now.Before(entry.expiresAt) {
+		c.mu.Unlock()
+		return entry.validAfter, nil
+	}
+	c.mu.Unlock()
+
+	validAfter, err := c.redis.GetTokensValidAfter(userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sweep(now)
+	c.watermarks[userID] = watermarkEntry{
+		validAfter: validAfter,
+		expiresAt:  now.Add(c.ttl),
+	}
+
+	return validAfter, nil
+}
+
+// sweep drops stale entries once the cache grows large. Callers must hold mu.
+func (c *revocationCache) sweep(now time.Time) {
+	if len(c.denied)+len(c.allowed)+len(c.watermarks) < revocationCacheSweepSize {
+		return
+	}
+
+	for jti, until := range c.denied {
+		if now.After(until) {
+			delete(c.denied, jti)
+		}
+	}
+
+	for jti, until := range c.allowed {
+		if now.After(until) {
+			delete(c.allowed, jti)
+		}
+	}
+
+	for userID, entry := range c.watermarks {
+		if now.After(entry.expiresAt) {
+			delete(c.watermarks, userID)
+		}
+	}
+}